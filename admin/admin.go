@@ -0,0 +1,140 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/meklis/all-ok-radius-server/logger"
+	"github.com/meklis/all-ok-radius-server/prom"
+	"github.com/meklis/all-ok-radius-server/radius"
+	"github.com/meklis/all-ok-radius-server/radius/events"
+)
+
+type AdminConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	ListenAddr string `yaml:"listen_addr"`
+	Token      string `yaml:"token"`
+}
+
+// Validate проверяет настройки включенного admin API. Токен не может быть пустым или содержать
+// незамененную переменную окружения ${...} - такая строка стала бы известным всем токеном
+func (c AdminConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.ListenAddr == "" {
+		return fmt.Errorf("admin.listen_addr is empty")
+	}
+	if c.Token == "" {
+		return fmt.Errorf("admin.token is empty")
+	}
+	if unresolvedEnvRe.MatchString(c.Token) {
+		return fmt.Errorf("admin.token contains unresolved environment variable %v", unresolvedEnvRe.FindString(c.Token))
+	}
+	return nil
+}
+
+var unresolvedEnvRe = regexp.MustCompile(`\$\{[^}]*\}`)
+
+// Admin - HTTP-интерфейс управления радиусом.
+// Все запросы требуют заголовок Authorization: Bearer <token>
+type Admin struct {
+	conf AdminConfig
+	rad  *radius.Radius
+	lg   *logger.Logger
+
+	reload func() error
+}
+
+type response struct {
+	Data       interface{} `json:"data"`
+	Error      string      `json:"error,omitempty"`
+	StatusCode int         `json:"statusCode"`
+}
+
+func Init(conf AdminConfig, rad *radius.Radius, lg *logger.Logger) *Admin {
+	a := new(Admin)
+	a.conf = conf
+	a.rad = rad
+	a.lg = lg
+	return a
+}
+
+// SetReload задает функцию перечитывания конфигурации для /config/reload
+func (a *Admin) SetReload(reload func() error) *Admin {
+	a.reload = reload
+	return a
+}
+
+func (a *Admin) ListenAndServe() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dyn-auth/disconnect", a.auth(a.dynAuthHandler(a.rad.Disconnect)))
+	mux.HandleFunc("/dyn-auth/coa", a.auth(a.dynAuthHandler(a.rad.CoA)))
+	if a.reload != nil {
+		mux.HandleFunc("/config/reload", a.auth(a.reloadHandler))
+	}
+	a.lg.NoticeF("Admin API started on %v", a.conf.ListenAddr)
+	return http.ListenAndServe(a.conf.ListenAddr, mux)
+}
+
+func (a *Admin) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if a.conf.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(a.conf.Token)) != 1 {
+			a.lg.WarningF("admin: unauthorized request %v from %v", r.URL.Path, r.RemoteAddr)
+			a.writeResponse(w, http.StatusUnauthorized, nil, "unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (a *Admin) dynAuthHandler(send func(context.Context, events.DynAuthRequest) (*events.DynAuthResponse, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			a.writeResponse(w, http.StatusMethodNotAllowed, nil, "method not allowed")
+			return
+		}
+		var req events.DynAuthRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			a.writeResponse(w, http.StatusBadRequest, nil, err.Error())
+			return
+		}
+		resp, err := send(r.Context(), req)
+		if err != nil {
+			prom.ErrorsInc(prom.Warning, "admin")
+			a.lg.WarningF("admin: %v for NAS %v failed: %v", r.URL.Path, req.NasIp, err.Error())
+			a.writeResponse(w, http.StatusBadGateway, nil, err.Error())
+			return
+		}
+		a.writeResponse(w, http.StatusOK, resp, "")
+	}
+}
+
+func (a *Admin) reloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		a.writeResponse(w, http.StatusMethodNotAllowed, nil, "method not allowed")
+		return
+	}
+	a.lg.NoticeF("admin: reloading configuration, requested from %v", r.RemoteAddr)
+	if err := a.reload(); err != nil {
+		prom.ErrorsInc(prom.Warning, "admin")
+		a.lg.ErrorF("admin: configuration is not reloaded: %v", err.Error())
+		a.writeResponse(w, http.StatusBadRequest, nil, err.Error())
+		return
+	}
+	a.writeResponse(w, http.StatusOK, "reloaded", "")
+}
+
+func (a *Admin) writeResponse(w http.ResponseWriter, statusCode int, data interface{}, errMsg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response{Data: data, Error: errMsg, StatusCode: statusCode}); err != nil {
+		a.lg.ErrorF("admin: error write response: %v", err.Error())
+	}
+}
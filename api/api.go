@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"github.com/imroc/req"
@@ -13,17 +14,21 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Api struct {
 	sync.Mutex
 	Conf            ApiConfig
+	confLock        sync.RWMutex
 	cache           *cache.CacheApi
 	sources         *sources.Sources
 	lg              *logger.Logger
 	postAuthChannel chan *PostAuth
-	acctChannel     chan *events.AcctRequest
+	acctChannel     chan *acctJob
+	//Количество событий PostAuth и accounting в очередях и в процессе отправки
+	pendingEvents int64
 }
 
 func Init(conf ApiConfig, lg *logger.Logger) *Api {
@@ -54,7 +59,7 @@ func Init(conf ApiConfig, lg *logger.Logger) *Api {
 				htReq := req.New()
 				for {
 					auth := <-api.postAuthChannel
-					for _, addr := range conf.PostAuth.Addresses {
+					for _, addr := range api.config().PostAuth.Addresses {
 						response, err := htReq.Post(addr, req.BodyJSON(auth))
 						if err != nil {
 							prom.ErrorsInc(prom.Error, "api")
@@ -67,6 +72,7 @@ func Init(conf ApiConfig, lg *logger.Logger) *Api {
 							continue
 						}
 					}
+					atomic.AddInt64(&api.pendingEvents, -1)
 				}
 			}()
 		}
@@ -82,26 +88,33 @@ func Init(conf ApiConfig, lg *logger.Logger) *Api {
 
 	//Init acct readers
 	if conf.Acct.Enabled {
-		api.acctChannel = make(chan *events.AcctRequest, 100)
+		api.acctChannel = make(chan *acctJob, 100)
 		lg.NoticeF("start acct readers")
 		for i := 0; i < conf.Acct.CountReaders; i++ {
 			go func() {
 				htReq := req.New()
 				for {
-					acct := <-api.acctChannel
-					for _, addr := range conf.Acct.Addresses {
-						response, err := htReq.Post(addr, req.BodyJSON(acct))
+					job := <-api.acctChannel
+					var lastErr error
+					for _, addr := range api.config().Acct.Addresses {
+						response, err := htReq.Post(addr, req.BodyJSON(job.request))
 						if err != nil {
 							prom.ErrorsInc(prom.Error, "api")
 							lg.ErrorF("acct report returned err from addr %v: %v", addr, tracerr.Sprint(err))
+							lastErr = err
 							continue
 						}
 						if response.Response().StatusCode != 200 {
 							prom.ErrorsInc(prom.Error, "api")
-							lg.ErrorF("acct report returned err from addr %v: %v", addr, tracerr.Sprint(err))
+							lg.ErrorF("acct report returned status code %v from addr %v", response.Response().StatusCode, addr)
+							lastErr = fmt.Errorf("acct report returned status code %v from addr %v", response.Response().StatusCode, addr)
 							continue
 						}
 					}
+					if job.done != nil {
+						job.done <- lastErr
+					}
+					atomic.AddInt64(&api.pendingEvents, -1)
 				}
 			}()
 		}
@@ -119,10 +132,11 @@ func Init(conf ApiConfig, lg *logger.Logger) *Api {
 }
 
 func (a *Api) Get(req *events.AuthRequest) (*events.AuthResponse, error) {
+	conf := a.config()
 	hash := req.GetHash()
 	response := new(events.AuthResponse)
 	exist := false
-	if a.Conf.Auth.Caching.Enabled {
+	if conf.Auth.Caching.Enabled {
 		response, exist = a.cache.Get(hash)
 		if exist {
 			a.lg.DebugF("%v found in cache, check actual time", hash)
@@ -147,8 +161,8 @@ func (a *Api) Get(req *events.AuthRequest) (*events.AuthResponse, error) {
 		return nil, tracerr.Wrap(err)
 	}
 
-	if a.Conf.Auth.Caching.Enabled {
-		actualizeTime := time.Now().Add(a.Conf.Auth.Caching.ActualizeTimeout)
+	if conf.Auth.Caching.Enabled {
+		actualizeTime := time.Now().Add(conf.Auth.Caching.ActualizeTimeout)
 		if actualizeTime.After(time.Now().Add(time.Second * time.Duration(apiResp.LeaseTimeSec))) {
 			a.lg.Warningf("detected lease_time_sec has a small time. Actualize time will be set as lease time")
 			actualizeTime = time.Now().Add(time.Second * time.Duration(apiResp.LeaseTimeSec))
@@ -158,27 +172,120 @@ func (a *Api) Get(req *events.AuthRequest) (*events.AuthResponse, error) {
 	}
 	return apiResp, nil
 }
+
+// GetCached возвращает ответ из кеша без проверки времени актуализации и без обращения к API
+func (a *Api) GetCached(req *events.AuthRequest) (*events.AuthResponse, bool) {
+	if !a.config().Auth.Caching.Enabled {
+		return nil, false
+	}
+	return a.cache.Get(req.GetHash())
+}
+
+func (a *Api) config() ApiConfig {
+	a.confLock.RLock()
+	defer a.confLock.RUnlock()
+	return a.Conf
+}
+
+// Reload применяет новые адреса API, таймауты и настройки кеша. Кеш ответов сохраняется.
+// Включение/выключение postauth и acct и количество читателей очередей меняются только после перезапуска
+func (a *Api) Reload(conf ApiConfig) error {
+	a.confLock.Lock()
+	defer a.confLock.Unlock()
+	//Читатели очередей уже запущены, их количество не меняется
+	conf.PostAuth.Enabled, conf.PostAuth.CountReaders = a.Conf.PostAuth.Enabled, a.Conf.PostAuth.CountReaders
+	conf.Acct.Enabled, conf.Acct.CountReaders = a.Conf.Acct.Enabled, a.Conf.Acct.CountReaders
+	if err := conf.Validate(); err != nil {
+		return err
+	}
+	a.sources.Update(conf.Auth.Addresses, conf.Auth.AliveChecking.DisableTimeout)
+	a.cache.SetExpireTimeout(conf.Auth.Caching.TimeoutExpires)
+	a.Conf = conf
+	return nil
+}
+
+func (a *Api) IsAlive() bool {
+	return a.sources.HasAlive()
+}
+
 func (a *Api) SendPostAuth(auth *PostAuth) {
-	if !a.Conf.PostAuth.Enabled {
+	if !a.config().PostAuth.Enabled {
 		return
 	}
+	atomic.AddInt64(&a.pendingEvents, 1)
 	select {
 	case a.postAuthChannel <- auth:
 	default:
+		atomic.AddInt64(&a.pendingEvents, -1)
 		a.lg.WarningF("post auth channel is full! Try to increase reader count")
 		a.lg.DebugF("request %v-%v-%v will be dropped", auth.Request.NasIp, auth.Request.DeviceMac, auth.Request.DhcpServerName)
 	}
 }
 
-func (a *Api) SendAcct(acct *events.AcctRequest) {
-	if !a.Conf.Acct.Enabled {
-		return
+// SendAcct ставит запрос в очередь отправки на API.
+// В режиме ack_mode=delivered дожидается, пока запрос примут все адреса API.
+// Если вернулась ошибка - запрос не принят и на него не нужно отвечать NAS-у
+func (a *Api) SendAcct(acct *events.AcctRequest) error {
+	conf := a.config()
+	if !conf.Acct.Enabled {
+		return nil
+	}
+	job := &acctJob{request: acct}
+	if conf.Acct.AckMode == AcctAckDelivered {
+		job.done = make(chan error, 1)
 	}
+	atomic.AddInt64(&a.pendingEvents, 1)
 	select {
-	case a.acctChannel <- acct:
+	case a.acctChannel <- job:
 	default:
+		atomic.AddInt64(&a.pendingEvents, -1)
 		a.lg.WarningF("acct channel is full! Try to increase reader count")
 		a.lg.DebugF("acct %v-%v-%v with ip %v will be dropped ", acct.NasIp, acct.DeviceMac, acct.DhcpServerName, acct.FramedIpAddress)
+		return tracerr.New("acct channel is full")
+	}
+	if job.done == nil {
+		return nil
+	}
+	if err := <-job.done; err != nil {
+		return tracerr.Wrap(err)
+	}
+	return nil
+}
+
+// Shutdown ждет, пока события из очередей PostAuth и accounting будут отправлены на API.
+// Если ctx завершится раньше - оставшиеся в очередях события удаляются с записью в лог и возвращается ошибка
+func (a *Api) Shutdown(ctx context.Context) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for atomic.LoadInt64(&a.pendingEvents) > 0 {
+		select {
+		case <-ctx.Done():
+			postAuth, acct := a.dropQueues()
+			return tracerr.New(fmt.Sprintf("event queues are not flushed: dropped %v postauth and %v acct events, %v events were in sending",
+				postAuth, acct, atomic.LoadInt64(&a.pendingEvents)))
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func (a *Api) dropQueues() (postAuth int, acct int) {
+	for {
+		select {
+		case auth := <-a.postAuthChannel:
+			atomic.AddInt64(&a.pendingEvents, -1)
+			postAuth++
+			a.lg.WarningF("post auth %v-%v-%v with status %v dropped on shutdown", auth.Request.NasIp, auth.Request.DeviceMac, auth.Request.DhcpServerName, auth.Response.Status)
+		case job := <-a.acctChannel:
+			atomic.AddInt64(&a.pendingEvents, -1)
+			acct++
+			a.lg.WarningF("acct %v-%v-%v with status %v dropped on shutdown", job.request.NasIp, job.request.DeviceMac, job.request.SessionId, job.request.StatusType)
+			if job.done != nil {
+				job.done <- tracerr.New("acct dropped on shutdown")
+			}
+		default:
+			return postAuth, acct
+		}
 	}
 }
 
@@ -190,7 +297,7 @@ func (a *Api) _getFromApi(request *events.AuthRequest) (*events.AuthResponse, er
 	}
 	a.lg.DebugF("defined source from rr = %v", source)
 	a.sources.IncRequests(source.Address)
-	req.SetTimeout(a.Conf.Timeout)
+	req.SetTimeout(a.config().Timeout)
 	response, err := req.Post(source.Address, req.BodyJSON(request))
 	if err != nil {
 		prom.ErrorsInc(prom.Error, "api")
@@ -4,16 +4,19 @@ import (
 	"github.com/meklis/all-ok-radius-server/prom"
 	"github.com/meklis/all-ok-radius-server/radius/events"
 	"github.com/meklis/go-cache"
+	"sync/atomic"
 	"time"
 )
 
 type CacheApi struct {
-	responses *cache.Cache
+	responses     *cache.Cache
+	expireTimeout int64
 }
 
 func Init(expireTimeout time.Duration) *CacheApi {
 	c := new(CacheApi)
 	c.responses = cache.New(expireTimeout, 10*time.Minute)
+	c.expireTimeout = int64(expireTimeout)
 	go func() {
 		for {
 			prom.SetCacheSize(c.responses.ItemCount())
@@ -33,6 +36,12 @@ func (c *CacheApi) Get(hash string) (*events.AuthResponse, bool) {
 }
 
 func (c *CacheApi) Set(hash string, resp events.AuthResponse) *CacheApi {
-	c.responses.SetDefault(hash, resp)
+	c.responses.Set(hash, resp, time.Duration(atomic.LoadInt64(&c.expireTimeout)))
+	return c
+}
+
+// SetExpireTimeout меняет время жизни новых записей, уже сохраненные записи не удаляются
+func (c *CacheApi) SetExpireTimeout(expireTimeout time.Duration) *CacheApi {
+	atomic.StoreInt64(&c.expireTimeout, int64(expireTimeout))
 	return c
 }
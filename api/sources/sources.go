@@ -60,6 +60,33 @@ func (s *Sources) sourcesWatcher() {
 	}
 }
 
+// Update заменяет список адресов. Для адресов, которые остались в списке, сохраняется состояние и счетчик запросов
+func (s *Sources) Update(sources []string, disableTimeout time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	s.disableTimeOut = disableTimeout
+	updated := make(map[string]Source)
+	for _, addr := range sources {
+		if src, ok := s.sources[addr]; ok {
+			updated[addr] = src
+			continue
+		}
+		s.lg.NoticeF("source %v added", addr)
+		updated[addr] = Source{
+			Address:     addr,
+			IsAlive:     true,
+			Requests:    0,
+			DisableTime: time.Now(),
+		}
+	}
+	for addr := range s.sources {
+		if _, ok := updated[addr]; !ok {
+			s.lg.NoticeF("source %v removed", addr)
+		}
+	}
+	s.sources = updated
+}
+
 func (s *Sources) Disable(sourceName string) {
 	s.Lock()
 	defer s.Unlock()
@@ -94,6 +121,17 @@ func (a *Sources) GetSource() (*Source, error) {
 	})
 	return &slice[0], nil
 }
+func (s *Sources) HasAlive() bool {
+	s.Lock()
+	defer s.Unlock()
+	for _, src := range s.sources {
+		if src.IsAlive {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Sources) IncRequests(addr string) {
 	s.Lock()
 	defer s.Unlock()
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"github.com/meklis/all-ok-radius-server/radius/events"
 	"time"
 )
@@ -27,10 +28,36 @@ type ApiConfig struct {
 		Enabled      bool     `yaml:"enabled"`
 		CountReaders int      `yaml:"count_readers"`
 		Addresses    []string `yaml:"addresses"`
+		AckMode      string   `yaml:"ack_mode"`
 	} `yaml:"acct"`
 	Timeout time.Duration `yaml:"timeout"`
 }
 
+const (
+	// AcctAckEnqueue - Accounting-Response отправляется после постановки запроса в очередь
+	AcctAckEnqueue = "enqueue"
+	// AcctAckDelivered - Accounting-Response отправляется после того, как все адреса API приняли запрос
+	AcctAckDelivered = "delivered"
+)
+
+func (c ApiConfig) Validate() error {
+	switch c.Acct.AckMode {
+	case "", AcctAckEnqueue:
+	case AcctAckDelivered:
+		if c.Acct.Enabled && c.Acct.CountReaders <= 0 {
+			return fmt.Errorf("api.acct.ack_mode %v requires count_readers > 0", c.Acct.AckMode)
+		}
+	default:
+		return fmt.Errorf("unknown api.acct.ack_mode %v, must be %v or %v", c.Acct.AckMode, AcctAckEnqueue, AcctAckDelivered)
+	}
+	return nil
+}
+
+type acctJob struct {
+	request *events.AcctRequest
+	done    chan error
+}
+
 type ApiResponse struct {
 	Data       events.AuthResponse `json:"data"`
 	Meta       interface{}         `json:"meta"`
@@ -52,6 +79,9 @@ func InitPostAuth(req events.AuthRequest, resp events.AuthResponse) *PostAuth {
 		DhcpServerId:    req.DhcpServerId,
 		FramedIpAddress: req.FramedIpAddress,
 		Class:           req.Class,
+		MikrotikRealm:   req.MikrotikRealm,
+		MikrotikHostIp:  req.MikrotikHostIp,
+		Attributes:      req.Attributes,
 	}
 	p.Response = events.AuthResponse{
 		IpAddress:    resp.IpAddress,
@@ -60,6 +90,15 @@ func InitPostAuth(req events.AuthRequest, resp events.AuthResponse) *PostAuth {
 		Status:       resp.Status,
 		Error:        resp.Error,
 		Class:        resp.Class,
+		Reject:       resp.Reject,
+		ReplyMessage: resp.ReplyMessage,
+		RateLimit:    resp.RateLimit,
+		AddressList:  resp.AddressList,
+
+		Ipv6Address:         resp.Ipv6Address,
+		DelegatedIpv6Prefix: resp.DelegatedIpv6Prefix,
+		Ipv6PoolName:        resp.Ipv6PoolName,
+		Attributes:          resp.Attributes,
 	}
 	return p
 }
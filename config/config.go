@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
+	"github.com/meklis/all-ok-radius-server/admin"
 	"github.com/meklis/all-ok-radius-server/api"
 	"github.com/meklis/all-ok-radius-server/logger"
+	"github.com/meklis/all-ok-radius-server/radius"
 	"gopkg.in/yaml.v2"
 )
 
@@ -31,10 +34,26 @@ type Configuration struct {
 		Detailed                bool              `yaml:"detailed"`
 	} `yaml:"prometheus"`
 	Radius struct {
-		ListenAddr string `yaml:"listen_addr"`
-		Secret     string `yaml:"secret"`
+		ListenAddr        string                 `yaml:"listen_addr"`
+		Proto             string                 `yaml:"proto"`
+		Listeners         []radius.Listener      `yaml:"listeners"`
+		Secret            string                 `yaml:"secret"`
+		Clients           []radius.Client        `yaml:"clients"`
+		OnApiError        radius.FailurePolicy   `yaml:"on_api_error"`
+		RadSec            radius.RadSecConfig    `yaml:"radsec"`
+		DuplicateCacheTTL time.Duration          `yaml:"duplicate_cache_ttl"`
+		DynAuth           radius.DynAuthConfig   `yaml:"dyn_auth"`
+		Dictionaries      []string               `yaml:"dictionaries"`
+		ForwardAttributes bool                   `yaml:"forward_attributes"`
+		Proxy             radius.ProxyConfig     `yaml:"proxy"`
+		Option82          radius.Option82Config  `yaml:"option82"`
+		RateLimit         radius.RateLimitConfig `yaml:"rate_limit"`
+		Workers           radius.WorkersConfig   `yaml:"workers"`
 	} `yaml:"radius"`
-	Api api.ApiConfig `yaml:"api"`
+	Api   api.ApiConfig     `yaml:"api"`
+	Admin admin.AdminConfig `yaml:"admin"`
+
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
 
 	Profiler struct {
 		Port    int  `yaml:"port"`
@@ -43,23 +62,37 @@ type Configuration struct {
 }
 
 func LoadConfig(path string, Config *Configuration) error {
-	bytes, err := ioutil.ReadFile(path)
+	yamlConfig, err := readConfig(path)
 	if err != nil {
 		return err
 	}
-	yamlConfig := string(bytes)
-	for _, e := range os.Environ() {
-		pair := strings.SplitN(e, "=", 2)
-		yamlConfig = strings.ReplaceAll(yamlConfig, fmt.Sprintf("${%v}", pair[0]), pair[1])
-	}
-	err = yaml.Unmarshal([]byte(yamlConfig), &Config)
 	fmt.Printf(`Loaded configuration from %v with env readed:
 %v
 `, path, yamlConfig)
+	return yaml.Unmarshal([]byte(yamlConfig), &Config)
+}
+
+// ReloadConfig читает конфигурацию так же, как LoadConfig, но не выводит ее:
+// при перезагрузке в лог не должны попадать секреты клиентов и API
+func ReloadConfig(path string, Config *Configuration) error {
+	yamlConfig, err := readConfig(path)
 	if err != nil {
 		return err
 	}
-	return nil
+	return yaml.Unmarshal([]byte(yamlConfig), &Config)
+}
+
+func readConfig(path string) (string, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	yamlConfig := string(bytes)
+	for _, e := range os.Environ() {
+		pair := strings.SplitN(e, "=", 2)
+		yamlConfig = strings.ReplaceAll(yamlConfig, fmt.Sprintf("${%v}", pair[0]), pair[1])
+	}
+	return yamlConfig, nil
 }
 
 func ConfigureLogger(conf *Configuration) *logger.Logger {
@@ -81,3 +114,96 @@ func ConfigureLogger(conf *Configuration) *logger.Logger {
 		return lg
 	}
 }
+
+// Validate проверяет конфигурацию, не применяя ее
+func Validate(conf *Configuration) error {
+	if err := conf.Api.Validate(); err != nil {
+		return err
+	}
+	if _, err := radius.NewClients(conf.Radius.Clients, conf.Radius.Secret, nil); err != nil {
+		return err
+	}
+	if err := conf.Radius.OnApiError.Validate(); err != nil {
+		return err
+	}
+	if err := conf.Radius.RateLimit.Validate(); err != nil {
+		return err
+	}
+	for _, l := range conf.Radius.Listeners {
+		if err := l.Validate(); err != nil {
+			return err
+		}
+	}
+	if len(conf.Radius.Listeners) == 0 {
+		if err := (radius.Listener{ListenAddr: conf.Radius.ListenAddr, Proto: conf.Radius.Proto}).Validate(); err != nil {
+			return err
+		}
+	}
+	if err := conf.Radius.RadSec.Validate(); err != nil {
+		return err
+	}
+	if _, err := radius.LoadDictionary(conf.Radius.Dictionaries); err != nil {
+		return err
+	}
+	if err := conf.Radius.Proxy.Validate(); err != nil {
+		return err
+	}
+	if err := conf.Radius.Option82.Validate(); err != nil {
+		return err
+	}
+	if err := conf.Admin.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reloaded возвращает измененные секции, которые применяются при перезагрузке
+func Reloaded(old, new *Configuration) []string {
+	sections := make([]string, 0)
+	oldApi, newApi := old.Api, new.Api
+	oldApi.PostAuth.Enabled, oldApi.PostAuth.CountReaders = newApi.PostAuth.Enabled, newApi.PostAuth.CountReaders
+	oldApi.Acct.Enabled, oldApi.Acct.CountReaders = newApi.Acct.Enabled, newApi.Acct.CountReaders
+	if !reflect.DeepEqual(oldApi, newApi) {
+		sections = append(sections, "api")
+	}
+	if !reflect.DeepEqual(old.Radius.Clients, new.Radius.Clients) || old.Radius.Secret != new.Radius.Secret {
+		sections = append(sections, "radius.clients")
+	}
+	if new.Logger.Console.Enabled && old.Logger.Console.LogLevel != new.Logger.Console.LogLevel {
+		sections = append(sections, "logger.console.log_level")
+	}
+	return sections
+}
+
+// NotReloadable возвращает секции, изменения в которых применяются только после перезапуска.
+// При перезагрузке применяются адреса и таймауты API, настройки кеша, клиенты и секреты, уровень логирования
+func NotReloadable(old, new *Configuration) []string {
+	sections := make([]string, 0)
+	oldLogger, newLogger := old.Logger, new.Logger
+	oldLogger.Console.LogLevel, newLogger.Console.LogLevel = 0, 0
+	if !reflect.DeepEqual(oldLogger, newLogger) {
+		sections = append(sections, "logger")
+	}
+	if !reflect.DeepEqual(old.Prometheus, new.Prometheus) {
+		sections = append(sections, "prometheus")
+	}
+	oldRadius, newRadius := old.Radius, new.Radius
+	oldRadius.Clients, newRadius.Clients = nil, nil
+	oldRadius.Secret, newRadius.Secret = "", ""
+	if !reflect.DeepEqual(oldRadius, newRadius) {
+		sections = append(sections, "radius")
+	}
+	if old.Api.PostAuth.Enabled != new.Api.PostAuth.Enabled || old.Api.PostAuth.CountReaders != new.Api.PostAuth.CountReaders {
+		sections = append(sections, "api.postauth")
+	}
+	if old.Api.Acct.Enabled != new.Api.Acct.Enabled || old.Api.Acct.CountReaders != new.Api.Acct.CountReaders {
+		sections = append(sections, "api.acct")
+	}
+	if !reflect.DeepEqual(old.Admin, new.Admin) {
+		sections = append(sections, "admin")
+	}
+	if !reflect.DeepEqual(old.Profiler, new.Profiler) {
+		sections = append(sections, "profiler")
+	}
+	return sections
+}
@@ -63,7 +63,7 @@ type Worker struct {
 	Color      int
 	format     string
 	timeFormat string
-	level      LogLevel
+	level      int64
 }
 
 // Info class, Contains all the info on what has to logged, time is the current time, Module is the specific module
@@ -195,17 +195,17 @@ func (l *Logger) SetFormat(format string) {
 }
 
 func (w *Worker) SetLogLevel(level LogLevel) {
-	w.level = level
+	atomic.StoreInt64(&w.level, int64(level))
 }
 
 func (l *Logger) SetLogLevel(level LogLevel) {
-	l.worker.level = level
+	l.worker.SetLogLevel(level)
 }
 
 // Function of Worker class to log a string based on level
 func (w *Worker) Log(level LogLevel, calldepth int, info *Info) error {
 
-	if w.level < level {
+	if LogLevel(atomic.LoadInt64(&w.level)) < level {
 		return nil
 	}
 
@@ -0,0 +1,4 @@
+//go:generate go run generate_main.go
+
+// Package mikrotik - атрибуты MikroTik (vendor 14988), сгенерированные из dictionary.mikrotik
+package mikrotik
@@ -0,0 +1,58 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"layeh.com/radius/dictionary"
+	"layeh.com/radius/dictionarygen"
+)
+
+// Генерирует из dictionary.mikrotik функции атрибутов (generated.go)
+// и константу Dictionary с текстом словаря для встроенного словаря радиуса (dictionary.go)
+func main() {
+	contents, err := ioutil.ReadFile("dictionary.mikrotik")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if strings.Contains(string(contents), "`") {
+		log.Fatal("dictionary.mikrotik must not contain backquotes")
+	}
+
+	parser := dictionary.Parser{
+		Opener: &dictionary.FileSystemOpener{},
+	}
+	dict, err := parser.ParseFile("dictionary.mikrotik")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(dict.Vendors) != 1 || dict.Vendors[0].Number != 14988 {
+		log.Fatal("expected dictionary to contain vendor 14988")
+	}
+
+	gen := dictionarygen.Generator{
+		Package: "mikrotik",
+	}
+	generated, err := gen.Generate(dict)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("generated.go", generated, 0644); err != nil {
+		log.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by generate_main.go. DO NOT EDIT.\n\n")
+	b.WriteString("package mikrotik\n\n")
+	b.WriteString("// Dictionary - словарь MikroTik в формате FreeRADIUS (dictionary.mikrotik)\n")
+	fmt.Fprintf(&b, "const Dictionary = `\n%s`\n", contents)
+	if err := ioutil.WriteFile("dictionary.go", b.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
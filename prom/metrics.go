@@ -62,6 +62,66 @@ var (
 		Name: "rad_mac_server_count",
 		Help: "Detailed requests count info by MAC - DHCP-server",
 	}, []string{"host", "mac", "server_name", "response_type"})
+	radUnknownClients = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_unknown_client_count",
+		Help: "Count of dropped packets from unknown clients",
+	}, []string{"reason"})
+	radRejects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_reject_count",
+		Help: "Count of Access-Reject responses",
+	}, []string{"host"})
+	radStatusServer = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_status_server_count",
+		Help: "Count of Status-Server requests by API status",
+	}, []string{"host", "status"})
+	radListenerRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_listener_requests_count",
+		Help: "Count of requests by listener and packet code",
+	}, []string{"listener", "code"})
+	radListenerDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_listener_dropped_count",
+		Help: "Count of requests dropped by listener role",
+	}, []string{"listener", "code"})
+	radDuplicates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_duplicate_requests_count",
+		Help: "Count of retransmitted requests",
+	}, []string{"host", "status"})
+	radMessageAuthenticatorDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_message_authenticator_dropped_count",
+		Help: "Count of packets dropped by Message-Authenticator check",
+	}, []string{"host", "reason"})
+	radDynAuth = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_dyn_auth_count",
+		Help: "Count of Disconnect/CoA requests sent to NAS by result",
+	}, []string{"host", "code", "result"})
+	radProxy = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_proxy_requests_count",
+		Help: "Count of requests proxied to upstream RADIUS servers by result",
+	}, []string{"upstream", "address", "result"})
+	radProxyAliveStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rad_proxy_upstream_alive_status",
+		Help: "Upstream RADIUS server address alive status",
+	}, []string{"upstream", "address"})
+	radRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_rate_limited_count",
+		Help: "Count of Access-Requests over rate limit by NAS, limit and action",
+	}, []string{"host", "limit", "action"})
+	radRateLimitedMac = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_rate_limited_mac_count",
+		Help: "Count of Access-Requests over rate limit by NAS and MAC",
+	}, []string{"host", "mac"})
+	radWorkerQueueLen = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rad_worker_queue_size",
+		Help: "Count of requests waiting for a free worker",
+	}, []string{})
+	radWorkerInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rad_worker_in_flight",
+		Help: "Count of requests processed by workers now",
+	}, []string{})
+	radWorkerShed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rad_worker_shed_count",
+		Help: "Count of requests dropped by overload, by reason",
+	}, []string{"reason"})
 	PromEnabled                bool
 	PromDetailedMacInfoEnabled bool
 )
@@ -111,6 +171,96 @@ func RadDetailedRequest(host, serverName, macAddr, responseType string) {
 	radDetailedRequests.With(map[string]string{"host": host, "server_name": serverName, "mac": macAddr, "response_type": responseType}).Inc()
 }
 
+// RadUnknownClientInc - reason: address (адрес не найден в radius.clients) или certificate (RadSec).
+// Адрес источника в метку не попадает - на UDP его можно подделать и создать неограниченное число серий
+func RadUnknownClientInc(reason string) {
+	if !PromEnabled {
+		return
+	}
+	radUnknownClients.With(map[string]string{"reason": reason}).Inc()
+}
+
+func RadRejectsInc(host string) {
+	if !PromEnabled {
+		return
+	}
+	radRejects.With(map[string]string{"host": host}).Inc()
+}
+
+func RadStatusServerInc(host string, alive bool) {
+	if !PromEnabled {
+		return
+	}
+	status := "alive"
+	if !alive {
+		status = "dead"
+	}
+	radStatusServer.With(map[string]string{"host": host, "status": status}).Inc()
+}
+
+func RadListenerRequestsInc(listener, code string) {
+	if !PromEnabled {
+		return
+	}
+	radListenerRequests.With(map[string]string{"listener": listener, "code": code}).Inc()
+}
+
+func RadListenerDroppedInc(listener, code string) {
+	if !PromEnabled {
+		return
+	}
+	radListenerDropped.With(map[string]string{"listener": listener, "code": code}).Inc()
+}
+
+func RadDuplicatesInc(host, status string) {
+	if !PromEnabled {
+		return
+	}
+	radDuplicates.With(map[string]string{"host": host, "status": status}).Inc()
+}
+
+func RadMessageAuthenticatorDroppedInc(host, reason string) {
+	if !PromEnabled {
+		return
+	}
+	radMessageAuthenticatorDropped.With(map[string]string{"host": host, "reason": reason}).Inc()
+}
+
+func RadDynAuthInc(host, code, result string) {
+	if !PromEnabled {
+		return
+	}
+	radDynAuth.With(map[string]string{"host": host, "code": code, "result": result}).Inc()
+}
+
+func RadProxyInc(upstream, address, result string) {
+	if !PromEnabled {
+		return
+	}
+	radProxy.With(map[string]string{"upstream": upstream, "address": address, "result": result}).Inc()
+}
+
+func SetProxyUpstreamStatus(upstream, address string, alive bool) {
+	if !PromEnabled {
+		return
+	}
+	status := 1
+	if !alive {
+		status = 0
+	}
+	radProxyAliveStatus.With(map[string]string{"upstream": upstream, "address": address}).Set(float64(status))
+}
+
+func RadRateLimitedInc(host, mac, limit, action string) {
+	if !PromEnabled {
+		return
+	}
+	radRateLimited.With(map[string]string{"host": host, "limit": limit, "action": action}).Inc()
+	if PromDetailedMacInfoEnabled {
+		radRateLimitedMac.With(map[string]string{"host": host, "mac": mac}).Inc()
+	}
+}
+
 func RadRequestsPoolInc(host string) {
 	if !PromEnabled {
 		return
@@ -150,6 +300,25 @@ func SetAcctQueueSize(size int) {
 	apiAcctQueueLen.With(map[string]string{}).Set(float64(size))
 }
 
+func SetWorkerQueueSize(size int) {
+	if !PromEnabled {
+		return
+	}
+	radWorkerQueueLen.With(map[string]string{}).Set(float64(size))
+}
+func SetWorkerInFlight(count int) {
+	if !PromEnabled {
+		return
+	}
+	radWorkerInFlight.With(map[string]string{}).Set(float64(count))
+}
+func RadWorkerShedInc(reason string) {
+	if !PromEnabled {
+		return
+	}
+	radWorkerShed.With(map[string]string{"reason": reason}).Inc()
+}
+
 func SetApiStatus(address string, alive bool) {
 	if !PromEnabled {
 		return
@@ -0,0 +1,205 @@
+package radius
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/logger"
+	"github.com/meklis/all-ok-radius-server/prom"
+	"github.com/meklis/go-cache"
+)
+
+// Client - описание NAS-а (микротика), которому разрешено отправлять запросы на радиус
+type Client struct {
+	Address      string   `yaml:"address"`
+	Secret       string   `yaml:"secret"`
+	Name         string   `yaml:"name"`
+	Tags         []string `yaml:"tags"`
+	CertIdentity string   `yaml:"cert_identity"`
+
+	RequireMessageAuthenticator bool `yaml:"require_message_authenticator"`
+}
+
+type clientEntry struct {
+	network *net.IPNet
+	client  Client
+}
+
+// Clients - таблица NAS-клиентов, используется как radius.SecretSource
+type Clients struct {
+	sync.RWMutex
+	entries []clientEntry
+	lg      *logger.Logger
+
+	//Адреса неизвестных клиентов, о которых уже написано в лог
+	unknownWarned *cache.Cache
+}
+
+// Предупреждение о неизвестном клиенте пишется не чаще раза в unknownClientWarnInterval на адрес
+// и не более чем для maxUnknownClientWarnings адресов за интервал
+const (
+	unknownClientWarnInterval = time.Minute
+	maxUnknownClientWarnings  = 1000
+)
+
+// NewClients создает таблицу клиентов.
+// Если список клиентов пуст, используется defaultSecret для запросов с любого адреса
+func NewClients(clients []Client, defaultSecret string, lg *logger.Logger) (*Clients, error) {
+	c := new(Clients)
+	c.lg = lg
+	c.unknownWarned = cache.New(unknownClientWarnInterval, unknownClientWarnInterval)
+	entries, err := parseClients(clients, defaultSecret)
+	if err != nil {
+		return nil, err
+	}
+	c.entries = entries
+	return c, nil
+}
+
+// Reload заменяет таблицу клиентов таблицей, заранее построенной NewClients,
+// поэтому при перезагрузке ошибка в списке клиентов обнаруживается до применения конфигурации
+func (c *Clients) Reload(from *Clients) {
+	from.RLock()
+	entries := from.entries
+	from.RUnlock()
+	c.Lock()
+	defer c.Unlock()
+	c.entries = entries
+}
+
+func parseClients(clients []Client, defaultSecret string) ([]clientEntry, error) {
+	if len(clients) == 0 {
+		_, v4, _ := net.ParseCIDR("0.0.0.0/0")
+		_, v6, _ := net.ParseCIDR("::/0")
+		return []clientEntry{
+			{network: v4, client: Client{Address: v4.String(), Secret: defaultSecret, Name: "default"}},
+			{network: v6, client: Client{Address: v6.String(), Secret: defaultSecret, Name: "default"}},
+		}, nil
+	}
+	entries := make([]clientEntry, 0, len(clients))
+	for _, cl := range clients {
+		//Клиент только для RadSec (cert_identity без адреса) определяется по сертификату и в Find не участвует
+		var network *net.IPNet
+		if cl.Address != "" || cl.CertIdentity == "" {
+			var err error
+			if network, err = parseClientAddress(cl.Address); err != nil {
+				return nil, err
+			}
+		}
+		if cl.Secret == "" && cl.CertIdentity == "" {
+			return nil, fmt.Errorf("client %v has empty secret", cl.Address)
+		}
+		if cl.Name == "" {
+			cl.Name = cl.Address
+		}
+		if cl.Name == "" {
+			cl.Name = cl.CertIdentity
+		}
+		entries = append(entries, clientEntry{network: network, client: cl})
+	}
+	return entries, nil
+}
+
+func parseClientAddress(address string) (*net.IPNet, error) {
+	if strings.Contains(address, "/") {
+		_, network, err := net.ParseCIDR(address)
+		if err != nil {
+			return nil, fmt.Errorf("client address %v is not valid CIDR: %v", address, err)
+		}
+		return network, nil
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, fmt.Errorf("client address %v is not valid IP", address)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// Find возвращает клиента с самым длинным совпадающим префиксом
+func (c *Clients) Find(ip net.IP) (*Client, bool) {
+	if ip == nil {
+		return nil, false
+	}
+	c.RLock()
+	defer c.RUnlock()
+	var found *clientEntry
+	foundPrefix := -1
+	for i, e := range c.entries {
+		if e.network == nil || !e.network.Contains(ip) {
+			continue
+		}
+		if prefix, _ := e.network.Mask.Size(); prefix > foundPrefix {
+			found = &c.entries[i]
+			foundPrefix = prefix
+		}
+	}
+	if found == nil {
+		return nil, false
+	}
+	cl := found.client
+	return &cl, true
+}
+
+// FindByIdentity возвращает клиента, cert_identity которого совпадает с одним из имен сертификата (RadSec)
+func (c *Clients) FindByIdentity(identities []string) (*Client, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	for _, e := range c.entries {
+		if e.client.CertIdentity == "" {
+			continue
+		}
+		for _, identity := range identities {
+			if strings.EqualFold(e.client.CertIdentity, identity) {
+				cl := e.client
+				return &cl, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// RADIUSSecret реализует radius.SecretSource.
+// Для неизвестных адресов возвращается пустой секрет - пакет будет отброшен
+func (c *Clients) RADIUSSecret(ctx context.Context, remoteAddr net.Addr) ([]byte, error) {
+	ip := addrIP(remoteAddr)
+	cl, ok := c.Find(ip)
+	if !ok || cl.Secret == "" {
+		prom.RadUnknownClientInc("address")
+		c.warnUnknown(ip.String())
+		return nil, nil
+	}
+	return []byte(cl.Secret), nil
+}
+
+// warnUnknown пишет предупреждение о неизвестном клиенте. Адрес источника UDP можно подделать,
+// поэтому повторные предупреждения для адреса подавляются, а число адресов в интервале ограничено
+func (c *Clients) warnUnknown(ip string) {
+	if c.unknownWarned.ItemCount() >= maxUnknownClientWarnings {
+		return
+	}
+	if err := c.unknownWarned.Add(ip, struct{}{}, cache.DefaultExpiration); err != nil {
+		return
+	}
+	c.lg.WarningF("packets from unknown client %v dropped, next warnings for this address are suppressed for %v", ip, unknownClientWarnInterval)
+}
+
+func addrIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.IP
+	case *net.TCPAddr:
+		return a.IP
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,525 @@
+package radius
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/mikrotik"
+	"github.com/meklis/all-ok-radius-server/radius/events"
+	"github.com/ztrue/tracerr"
+	"layeh.com/radius"
+	"layeh.com/radius/dictionary"
+	"layeh.com/radius/rfc2865"
+)
+
+// Встроенный словарь атрибутов (формат FreeRADIUS): атрибуты ответа, которые API может вернуть в attributes,
+// и атрибуты запроса, которые передаются на API при radius.forward_attributes.
+// Атрибуты MikroTik берутся из словаря пакета mikrotik, по которому сгенерированы его функции.
+// Дополнительные словари подключаются через radius.dictionaries
+const builtinDictionary = baseDictionary + mikrotik.Dictionary
+
+const baseDictionary = `
+# RFC 2865
+ATTRIBUTE	User-Name				1	string
+ATTRIBUTE	NAS-IP-Address				4	ipaddr
+ATTRIBUTE	NAS-Port				5	integer
+ATTRIBUTE	Service-Type				6	integer
+ATTRIBUTE	Framed-Protocol				7	integer
+ATTRIBUTE	Framed-IP-Address			8	ipaddr
+ATTRIBUTE	Framed-IP-Netmask			9	ipaddr
+ATTRIBUTE	Framed-Routing				10	integer
+ATTRIBUTE	Filter-Id				11	string
+ATTRIBUTE	Framed-MTU				12	integer
+ATTRIBUTE	Framed-Compression			13	integer
+ATTRIBUTE	Login-IP-Host				14	ipaddr
+ATTRIBUTE	Login-Service				15	integer
+ATTRIBUTE	Login-TCP-Port				16	integer
+ATTRIBUTE	Reply-Message				18	string
+ATTRIBUTE	Callback-Number				19	string
+ATTRIBUTE	Callback-Id				20	string
+ATTRIBUTE	Framed-Route				22	string
+ATTRIBUTE	Framed-IPX-Network			23	ipaddr
+ATTRIBUTE	State					24	octets
+ATTRIBUTE	Class					25	octets
+ATTRIBUTE	Session-Timeout				27	integer
+ATTRIBUTE	Idle-Timeout				28	integer
+ATTRIBUTE	Termination-Action			29	integer
+ATTRIBUTE	Called-Station-Id			30	string
+ATTRIBUTE	Calling-Station-Id			31	string
+ATTRIBUTE	NAS-Identifier				32	string
+ATTRIBUTE	Proxy-State				33	octets
+ATTRIBUTE	Login-LAT-Service			34	string
+ATTRIBUTE	Login-LAT-Node				35	string
+ATTRIBUTE	Login-LAT-Group				36	octets
+ATTRIBUTE	Framed-AppleTalk-Link			37	integer
+ATTRIBUTE	Framed-AppleTalk-Network		38	integer
+ATTRIBUTE	Framed-AppleTalk-Zone			39	string
+ATTRIBUTE	CHAP-Challenge				60	octets
+ATTRIBUTE	NAS-Port-Type				61	integer
+ATTRIBUTE	Port-Limit				62	integer
+ATTRIBUTE	Login-LAT-Port				63	string
+
+VALUE	Service-Type		Login-User		1
+VALUE	Service-Type		Framed-User		2
+VALUE	Service-Type		Callback-Login-User	3
+VALUE	Service-Type		Callback-Framed-User	4
+VALUE	Service-Type		Outbound-User		5
+VALUE	Service-Type		Administrative-User	6
+VALUE	Service-Type		NAS-Prompt-User		7
+VALUE	Service-Type		Authenticate-Only	8
+VALUE	Service-Type		Callback-NAS-Prompt	9
+VALUE	Service-Type		Call-Check		10
+VALUE	Service-Type		Callback-Administrative	11
+VALUE	Framed-Protocol		PPP			1
+VALUE	Framed-Protocol		SLIP			2
+VALUE	Termination-Action	Default			0
+VALUE	Termination-Action	RADIUS-Request		1
+VALUE	NAS-Port-Type		Async			0
+VALUE	NAS-Port-Type		Virtual			5
+VALUE	NAS-Port-Type		Ethernet		15
+VALUE	NAS-Port-Type		Wireless-802.11		19
+VALUE	NAS-Port-Type		PPPoEoE			32
+
+# RFC 2866
+ATTRIBUTE	Acct-Status-Type			40	integer
+ATTRIBUTE	Acct-Delay-Time				41	integer
+ATTRIBUTE	Acct-Input-Octets			42	integer
+ATTRIBUTE	Acct-Output-Octets			43	integer
+ATTRIBUTE	Acct-Session-Id				44	string
+ATTRIBUTE	Acct-Authentic				45	integer
+ATTRIBUTE	Acct-Session-Time			46	integer
+ATTRIBUTE	Acct-Input-Packets			47	integer
+ATTRIBUTE	Acct-Output-Packets			48	integer
+ATTRIBUTE	Acct-Terminate-Cause			49	integer
+ATTRIBUTE	Acct-Multi-Session-Id			50	string
+ATTRIBUTE	Acct-Link-Count				51	integer
+ATTRIBUTE	Acct-Interim-Interval			85	integer
+
+VALUE	Acct-Status-Type	Start			1
+VALUE	Acct-Status-Type	Stop			2
+VALUE	Acct-Status-Type	Interim-Update		3
+VALUE	Acct-Status-Type	Accounting-On		7
+VALUE	Acct-Status-Type	Accounting-Off		8
+VALUE	Acct-Authentic		RADIUS			1
+VALUE	Acct-Authentic		Local			2
+VALUE	Acct-Authentic		Remote			3
+VALUE	Acct-Terminate-Cause	User-Request		1
+VALUE	Acct-Terminate-Cause	Lost-Carrier		2
+VALUE	Acct-Terminate-Cause	Lost-Service		3
+VALUE	Acct-Terminate-Cause	Idle-Timeout		4
+VALUE	Acct-Terminate-Cause	Session-Timeout		5
+VALUE	Acct-Terminate-Cause	Admin-Reset		6
+VALUE	Acct-Terminate-Cause	Admin-Reboot		7
+VALUE	Acct-Terminate-Cause	Port-Error		8
+VALUE	Acct-Terminate-Cause	NAS-Error		9
+VALUE	Acct-Terminate-Cause	NAS-Request		10
+VALUE	Acct-Terminate-Cause	NAS-Reboot		11
+VALUE	Acct-Terminate-Cause	Port-Unneeded		12
+VALUE	Acct-Terminate-Cause	Port-Preempted		13
+VALUE	Acct-Terminate-Cause	Port-Suspended		14
+VALUE	Acct-Terminate-Cause	Service-Unavailable	15
+VALUE	Acct-Terminate-Cause	Callback		16
+VALUE	Acct-Terminate-Cause	User-Error		17
+VALUE	Acct-Terminate-Cause	Host-Request		18
+
+# RFC 2869
+ATTRIBUTE	Acct-Input-Gigawords			52	integer
+ATTRIBUTE	Acct-Output-Gigawords			53	integer
+ATTRIBUTE	Event-Timestamp				55	date
+ATTRIBUTE	Connect-Info				77	string
+ATTRIBUTE	NAS-Port-Id				87	string
+ATTRIBUTE	Framed-Pool				88	string
+
+# RFC 3162, RFC 4818
+ATTRIBUTE	NAS-IPv6-Address			95	ipv6addr
+ATTRIBUTE	Framed-Interface-Id			96	ifid
+ATTRIBUTE	Framed-IPv6-Prefix			97	ipv6prefix
+ATTRIBUTE	Login-IPv6-Host				98	ipv6addr
+ATTRIBUTE	Framed-IPv6-Route			99	string
+ATTRIBUTE	Framed-IPv6-Pool			100	string
+ATTRIBUTE	Delegated-IPv6-Prefix			123	ipv6prefix
+
+# RFC 6911
+ATTRIBUTE	Framed-IPv6-Address			168	ipv6addr
+ATTRIBUTE	DNS-Server-IPv6-Address			169	ipv6addr
+ATTRIBUTE	Route-IPv6-Information			170	ipv6prefix
+ATTRIBUTE	Delegated-IPv6-Prefix-Pool		171	string
+ATTRIBUTE	Stateful-IPv6-Address-Pool		172	string
+
+VENDOR		Redback				2352
+BEGIN-VENDOR	Redback
+ATTRIBUTE	Agent-Remote-Id				96	octets
+ATTRIBUTE	Agent-Circuit-Id			97	octets
+END-VENDOR	Redback
+`
+
+type dictAttribute struct {
+	name     string
+	vendor   uint32
+	typ      byte
+	dataType dictionary.AttributeType
+	values   map[string]uint32
+	names    map[uint32]string
+}
+
+type dictCode struct {
+	vendor uint32
+	typ    byte
+}
+
+// Dictionary - таблица атрибутов для кодирования дополнительных атрибутов ответа по имени
+// и декодирования атрибутов запроса
+type Dictionary struct {
+	attributes map[string]*dictAttribute
+	codes      map[dictCode]*dictAttribute
+	hidden     map[dictCode]bool
+}
+
+// Атрибуты с паролями и подписью пакета не декодируются и не передаются на API
+var hiddenAttributes = map[dictCode]bool{
+	{typ: byte(rfc2865.UserPassword_Type)}: true,
+	{typ: byte(rfc2865.CHAPPassword_Type)}: true,
+	{typ: 69}:                              true, // Tunnel-Password
+	{typ: 80}:                              true, // Message-Authenticator
+}
+
+// LoadDictionary загружает встроенный словарь и дополнительные словари в формате FreeRADIUS.
+// Атрибуты из дополнительных словарей переопределяют встроенные с тем же именем
+func LoadDictionary(files []string) (*Dictionary, error) {
+	d := &Dictionary{
+		attributes: make(map[string]*dictAttribute),
+		codes:      make(map[dictCode]*dictAttribute),
+		hidden:     make(map[dictCode]bool),
+	}
+	for code := range hiddenAttributes {
+		d.hidden[code] = true
+	}
+	parser := dictionary.Parser{Opener: memoryOpener{}}
+	builtin, err := parser.Parse(&memoryFile{Reader: bytes.NewReader([]byte(builtinDictionary)), name: "builtin"})
+	if err != nil {
+		return nil, tracerr.Wrap(err)
+	}
+	if err := d.add(builtin); err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		parser := dictionary.Parser{
+			Opener:                    &dictionary.FileSystemOpener{Root: filepath.Dir(file)},
+			IgnoreIdenticalAttributes: true,
+		}
+		dict, err := parser.ParseFile(file)
+		if err != nil {
+			return nil, tracerr.Wrap(err)
+		}
+		if err := d.add(dict); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func (d *Dictionary) add(dict *dictionary.Dictionary) error {
+	addAttrs := func(vendor uint32, attrs []*dictionary.Attribute, values []*dictionary.Value) {
+		for _, attr := range attrs {
+			// Вложенные (TLV) атрибуты не поддерживаются
+			if len(attr.OID) != 1 || attr.OID[0] < 1 || attr.OID[0] > 255 {
+				continue
+			}
+			code := dictCode{vendor: vendor, typ: byte(attr.OID[0])}
+			// Шифруемые и тегированные атрибуты не поддерживаются, шифруемые к тому же не передаются на API
+			if attr.FlagEncrypt.Valid || attr.HasTag() {
+				if attr.FlagEncrypt.Valid {
+					d.hidden[code] = true
+				}
+				continue
+			}
+			dictAttr := &dictAttribute{
+				name:     attr.Name,
+				vendor:   vendor,
+				typ:      code.typ,
+				dataType: attr.Type,
+				values:   make(map[string]uint32),
+				names:    make(map[uint32]string),
+			}
+			d.attributes[strings.ToLower(attr.Name)] = dictAttr
+			d.codes[code] = dictAttr
+		}
+		for _, value := range values {
+			if attr, ok := d.attributes[strings.ToLower(value.Attribute)]; ok {
+				attr.values[strings.ToLower(value.Name)] = uint32(value.Number)
+				attr.names[uint32(value.Number)] = value.Name
+			}
+		}
+	}
+	addAttrs(0, dict.Attributes, dict.Values)
+	for _, vendor := range dict.Vendors {
+		if vendor.GetTypeOctets() != 1 || vendor.GetLengthOctets() != 1 {
+			return tracerr.New(fmt.Sprintf("vendor %v has unsupported format", vendor.Name))
+		}
+		addAttrs(uint32(vendor.Number), vendor.Attributes, vendor.Values)
+	}
+	return nil
+}
+
+// Encode добавляет в пакет атрибут из ответа API
+func (d *Dictionary) Encode(p *radius.Packet, attribute events.ReplyAttribute) error {
+	attr, ok := d.attributes[strings.ToLower(attribute.Name)]
+	if !ok {
+		return tracerr.New(fmt.Sprintf("attribute %v not found in dictionary", attribute.Name))
+	}
+	value, err := attr.encodeValue(attribute.Value)
+	if err != nil {
+		return tracerr.New(fmt.Sprintf("attribute %v: %v", attr.name, err))
+	}
+	if attr.vendor == 0 {
+		p.Add(radius.Type(attr.typ), value)
+		return nil
+	}
+	if len(value) > 253-6 {
+		return tracerr.New(fmt.Sprintf("attribute %v: value too long", attr.name))
+	}
+	vsa := make(radius.Attribute, 2+len(value))
+	vsa[0] = attr.typ
+	vsa[1] = byte(len(vsa))
+	copy(vsa[2:], value)
+	vendorAttr, err := radius.NewVendorSpecific(attr.vendor, vsa)
+	if err != nil {
+		return tracerr.Wrap(err)
+	}
+	p.Add(rfc2865.VendorSpecific_Type, vendorAttr)
+	return nil
+}
+
+// Decode возвращает все атрибуты пакета по именам из словаря.
+// Неизвестные атрибуты называются Attr-<type> или Attr-26.<vendor>.<type> и передаются в hex
+func (d *Dictionary) Decode(p *radius.Packet) map[string][]string {
+	result := make(map[string][]string)
+	add := func(code dictCode, value radius.Attribute) {
+		if d.hidden[code] {
+			return
+		}
+		attr, ok := d.codes[code]
+		if !ok {
+			name := fmt.Sprintf("Attr-%v", code.typ)
+			if code.vendor != 0 {
+				name = fmt.Sprintf("Attr-26.%v.%v", code.vendor, code.typ)
+			}
+			result[name] = append(result[name], hex.EncodeToString(value))
+			return
+		}
+		result[attr.name] = append(result[attr.name], attr.decodeValue(value))
+	}
+	for typ, values := range p.Attributes {
+		for _, value := range values {
+			if typ != rfc2865.VendorSpecific_Type {
+				add(dictCode{typ: byte(typ)}, value)
+				continue
+			}
+			vendor, vsa, err := radius.VendorSpecific(value)
+			if err != nil {
+				add(dictCode{typ: byte(typ)}, value)
+				continue
+			}
+			// В одном Vendor-Specific может быть несколько атрибутов производителя
+			for len(vsa) >= 2 {
+				length := int(vsa[1])
+				if length < 2 || length > len(vsa) {
+					add(dictCode{typ: byte(typ)}, value)
+					break
+				}
+				add(dictCode{vendor: vendor, typ: vsa[0]}, vsa[2:length])
+				vsa = vsa[length:]
+			}
+		}
+	}
+	return result
+}
+
+func (a *dictAttribute) decodeValue(value radius.Attribute) string {
+	switch a.dataType {
+	case dictionary.AttributeString:
+		return radius.String(value)
+	case dictionary.AttributeIPAddr:
+		if ip, err := radius.IPAddr(value); err == nil {
+			return ip.String()
+		}
+	case dictionary.AttributeIPv6Addr:
+		if ip, err := radius.IPv6Addr(value); err == nil {
+			return ip.String()
+		}
+	case dictionary.AttributeIPv6Prefix:
+		if prefix, err := radius.IPv6Prefix(value); err == nil {
+			return prefix.String()
+		}
+	case dictionary.AttributeIFID:
+		if ifid, err := radius.IFID(value); err == nil {
+			return ifid.String()
+		}
+	case dictionary.AttributeInteger:
+		if i, err := radius.Integer(value); err == nil {
+			if name, ok := a.names[i]; ok {
+				return name
+			}
+			return strconv.FormatUint(uint64(i), 10)
+		}
+	case dictionary.AttributeInteger64:
+		if i, err := radius.Integer64(value); err == nil {
+			return strconv.FormatUint(i, 10)
+		}
+	case dictionary.AttributeDate:
+		if t, err := radius.Date(value); err == nil {
+			return strconv.FormatInt(t.Unix(), 10)
+		}
+	case dictionary.AttributeByte:
+		if len(value) == 1 {
+			return strconv.Itoa(int(value[0]))
+		}
+	case dictionary.AttributeShort:
+		if len(value) == 2 {
+			return strconv.Itoa(int(value[0])<<8 | int(value[1]))
+		}
+	}
+	return hex.EncodeToString(value)
+}
+
+func (a *dictAttribute) encodeValue(value interface{}) (radius.Attribute, error) {
+	switch a.dataType {
+	case dictionary.AttributeString:
+		return radius.NewString(valueToString(value))
+	case dictionary.AttributeOctets:
+		str := valueToString(value)
+		if strings.HasPrefix(str, "0x") {
+			b, err := hex.DecodeString(str[2:])
+			if err != nil {
+				return nil, err
+			}
+			return radius.NewBytes(b)
+		}
+		return radius.NewBytes([]byte(str))
+	case dictionary.AttributeIPAddr:
+		ip := net.ParseIP(valueToString(value))
+		if ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("'%v' is not valid IPv4 address", value)
+		}
+		return radius.NewIPAddr(ip)
+	case dictionary.AttributeIPv6Addr:
+		ip := net.ParseIP(valueToString(value))
+		if ip == nil {
+			return nil, fmt.Errorf("'%v' is not valid IPv6 address", value)
+		}
+		return radius.NewIPv6Addr(ip)
+	case dictionary.AttributeIPv6Prefix:
+		_, prefix, err := net.ParseCIDR(valueToString(value))
+		if err != nil {
+			return nil, err
+		}
+		return radius.NewIPv6Prefix(prefix)
+	case dictionary.AttributeIFID:
+		hw, err := net.ParseMAC(valueToString(value))
+		if err != nil {
+			return nil, err
+		}
+		return radius.NewIFID(hw)
+	case dictionary.AttributeInteger:
+		i, err := a.valueToInteger(value, math.MaxUint32)
+		if err != nil {
+			return nil, err
+		}
+		return radius.NewInteger(uint32(i)), nil
+	case dictionary.AttributeInteger64:
+		i, err := a.valueToInteger(value, math.MaxUint64)
+		if err != nil {
+			return nil, err
+		}
+		return radius.NewInteger64(i), nil
+	case dictionary.AttributeDate:
+		i, err := a.valueToInteger(value, math.MaxUint32)
+		if err != nil {
+			return nil, err
+		}
+		return radius.NewDate(time.Unix(int64(i), 0))
+	case dictionary.AttributeByte:
+		i, err := a.valueToInteger(value, math.MaxUint8)
+		if err != nil {
+			return nil, err
+		}
+		return radius.Attribute{byte(i)}, nil
+	case dictionary.AttributeShort:
+		i, err := a.valueToInteger(value, math.MaxUint16)
+		if err != nil {
+			return nil, err
+		}
+		return radius.Attribute{byte(i >> 8), byte(i)}, nil
+	}
+	return nil, fmt.Errorf("data type %v is not supported", a.dataType)
+}
+
+// valueToInteger принимает число, строку с числом или имя значения из словаря (VALUE)
+func (a *dictAttribute) valueToInteger(value interface{}, max uint64) (uint64, error) {
+	var i uint64
+	switch v := value.(type) {
+	case float64:
+		if v < 0 || v != math.Trunc(v) {
+			return 0, fmt.Errorf("'%v' is not valid unsigned integer", v)
+		}
+		i = uint64(v)
+	case int:
+		if v < 0 {
+			return 0, fmt.Errorf("'%v' is not valid unsigned integer", v)
+		}
+		i = uint64(v)
+	case string:
+		if named, ok := a.values[strings.ToLower(v)]; ok {
+			return uint64(named), nil
+		}
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("'%v' is not valid unsigned integer or value name", v)
+		}
+		i = parsed
+	default:
+		return 0, fmt.Errorf("'%v' is not valid integer", value)
+	}
+	if i > max {
+		return 0, fmt.Errorf("'%v' is out of range", value)
+	}
+	return i, nil
+}
+
+func valueToString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+type memoryOpener struct{}
+
+func (memoryOpener) OpenFile(name string) (dictionary.File, error) {
+	return nil, fmt.Errorf("including files is not supported in builtin dictionary (%v)", name)
+}
+
+type memoryFile struct {
+	*bytes.Reader
+	name string
+}
+
+func (f *memoryFile) Name() string {
+	return f.name
+}
+
+func (f *memoryFile) Close() error {
+	return nil
+}
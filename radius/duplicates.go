@@ -0,0 +1,89 @@
+package radius
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/prom"
+	"github.com/meklis/go-cache"
+	"layeh.com/radius"
+)
+
+// duplicateEntry - состояние обработки запроса, по которому могут прийти повторы
+type duplicateEntry struct {
+	sync.Mutex
+	reply *radius.Packet
+}
+
+// duplicateCache хранит ответы на запросы для повторной отправки на ретрансмиты (RFC 5080, 2.2.2).
+// Ключ - адрес и порт отправителя, Identifier и Authenticator запроса
+type duplicateCache struct {
+	entries *cache.Cache
+	ttl     time.Duration
+}
+
+func newDuplicateCache(ttl time.Duration) *duplicateCache {
+	return &duplicateCache{
+		entries: cache.New(ttl, time.Minute),
+		ttl:     ttl,
+	}
+}
+
+func duplicateKey(r *radius.Request) string {
+	return fmt.Sprintf("%v|%v|%x", r.RemoteAddr.String(), r.Identifier, r.Authenticator)
+}
+
+// start регистрирует запрос. Возвращает false и ранее созданную запись, если запрос является повтором
+func (d *duplicateCache) start(r *radius.Request) (*duplicateEntry, bool) {
+	key := duplicateKey(r)
+	entry := new(duplicateEntry)
+	if err := d.entries.Add(key, entry, d.ttl); err == nil {
+		return entry, true
+	}
+	if exist, ok := d.entries.Get(key); ok {
+		return exist.(*duplicateEntry), false
+	}
+	return entry, true
+}
+
+// release удаляет запись, если ответ не был отправлен - повтор запроса будет обработан заново
+func (d *duplicateCache) release(r *radius.Request, entry *duplicateEntry) {
+	entry.Lock()
+	defer entry.Unlock()
+	if entry.reply == nil {
+		d.entries.Delete(duplicateKey(r))
+	}
+}
+
+type duplicateResponseWriter struct {
+	radius.ResponseWriter
+	entry *duplicateEntry
+}
+
+func (w *duplicateResponseWriter) Write(packet *radius.Packet) error {
+	w.entry.Lock()
+	w.entry.reply = packet
+	w.entry.Unlock()
+	return w.ResponseWriter.Write(packet)
+}
+
+func (rad *Radius) _handleDuplicate(w radius.ResponseWriter, r *radius.Request, entry *duplicateEntry) {
+	host := addrIP(r.RemoteAddr).String()
+	entry.Lock()
+	reply := entry.reply
+	entry.Unlock()
+	//По UDP повтор с тем же адресом и Identifier отбрасывается сервером, пока первый запрос в обработке,
+	//поэтому запрос в обработке встречается только в TCP и RadSec
+	if reply == nil {
+		prom.RadDuplicatesInc(host, "in_progress")
+		rad.lg.DebugF("%v %x: retransmit from %v, request is still processing - ignored", r.Code, r.Authenticator, r.RemoteAddr)
+		return
+	}
+	prom.RadDuplicatesInc(host, "replayed")
+	rad.lg.DebugF("%v %x: retransmit from %v, sending cached %v", r.Code, r.Authenticator, r.RemoteAddr, reply.Code)
+	if err := w.Write(reply); err != nil {
+		prom.ErrorsInc(prom.Error, "radius")
+		rad.lg.ErrorF("error write cached response: %v", err.Error())
+	}
+}
@@ -0,0 +1,202 @@
+package radius
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/prom"
+	"github.com/meklis/all-ok-radius-server/radius/events"
+	"github.com/ztrue/tracerr"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+	"layeh.com/radius/rfc2866"
+	"layeh.com/radius/rfc2869"
+	"layeh.com/radius/rfc3576"
+	// регистрирует названия Error-Cause 407 и 508 из RFC 5176
+	_ "layeh.com/radius/rfc5176"
+)
+
+// DynAuthConfig - параметры отправки Disconnect-Request и CoA-Request на NAS (RFC 5176)
+type DynAuthConfig struct {
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout"`
+	Retry   time.Duration `yaml:"retry"`
+}
+
+// Disconnect отправляет на NAS Disconnect-Request
+func (rad *Radius) Disconnect(ctx context.Context, req events.DynAuthRequest) (*events.DynAuthResponse, error) {
+	return rad.SendDynAuth(ctx, radius.CodeDisconnectRequest, req)
+}
+
+// CoA отправляет на NAS CoA-Request
+func (rad *Radius) CoA(ctx context.Context, req events.DynAuthRequest) (*events.DynAuthResponse, error) {
+	return rad.SendDynAuth(ctx, radius.CodeCoARequest, req)
+}
+
+// SendDynAuth отправляет на NAS Disconnect-Request (code = radius.CodeDisconnectRequest) или CoA-Request
+// с секретом NAS-а из таблицы клиентов и возвращает результат (ACK/NAK и Error-Cause)
+func (rad *Radius) SendDynAuth(ctx context.Context, code radius.Code, req events.DynAuthRequest) (*events.DynAuthResponse, error) {
+	if code != radius.CodeDisconnectRequest && code != radius.CodeCoARequest {
+		return nil, tracerr.New(fmt.Sprintf("unsupported dynamic authorization code %v", code))
+	}
+	nasIp := net.ParseIP(req.NasIp)
+	if nasIp == nil {
+		return nil, tracerr.New(fmt.Sprintf("nas_ip '%v' is not valid IP", req.NasIp))
+	}
+	if rad.clients == nil {
+		return nil, tracerr.New("clients table is not configured")
+	}
+	client, ok := rad.clients.Find(nasIp)
+	if !ok || client.Secret == "" {
+		return nil, tracerr.New(fmt.Sprintf("not found secret for NAS %v", req.NasIp))
+	}
+
+	packet, err := rad.buildDynAuthPacket(code, []byte(client.Secret), req)
+	if err != nil {
+		return nil, err
+	}
+
+	port := req.DynAuthPort
+	if port == 0 {
+		port = rad.dynAuth.Port
+	}
+	if port == 0 {
+		port = 3799
+	}
+	timeout := rad.dynAuth.Timeout
+	if timeout == 0 {
+		timeout = 3 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	rad.lg.DebugF("%v: sending to NAS %v (%v), user=%v, session=%v", code, req.NasIp, client.Name, req.DeviceMac, req.AcctSessionId)
+	reply, err := exchangePacket(ctx, packet, net.JoinHostPort(nasIp.String(), strconv.Itoa(port)), rad.dynAuth.Retry)
+	if err != nil {
+		prom.RadDynAuthInc(req.NasIp, code.String(), "error")
+		return nil, tracerr.Wrap(err)
+	}
+	prom.RadDynAuthInc(req.NasIp, code.String(), reply.Code.String())
+
+	resp := &events.DynAuthResponse{
+		NasIp:        req.NasIp,
+		Code:         reply.Code.String(),
+		Ack:          reply.Code == radius.CodeDisconnectACK || reply.Code == radius.CodeCoAACK,
+		ReplyMessage: rfc2865.ReplyMessage_GetString(reply),
+	}
+	if cause, err := rfc3576.ErrorCause_Lookup(reply); err == nil {
+		resp.ErrorCause = int(cause)
+		resp.ErrorCauseName = cause.String()
+	}
+	rad.lg.DebugF("%v: response from NAS %v - %v, error_cause=%v", code, req.NasIp, resp.Code, resp.ErrorCauseName)
+	return resp, nil
+}
+
+func (rad *Radius) buildDynAuthPacket(code radius.Code, secret []byte, req events.DynAuthRequest) (*radius.Packet, error) {
+	packet := radius.New(code, secret)
+	if req.DeviceMac != "" {
+		if err := rfc2865.UserName_SetString(packet, req.DeviceMac); err != nil {
+			return nil, tracerr.Wrap(err)
+		}
+	}
+	if req.AcctSessionId != "" {
+		if err := rfc2866.AcctSessionID_SetString(packet, req.AcctSessionId); err != nil {
+			return nil, tracerr.Wrap(err)
+		}
+	}
+	if req.CallingStationId != "" {
+		if err := rfc2865.CallingStationID_SetString(packet, req.CallingStationId); err != nil {
+			return nil, tracerr.Wrap(err)
+		}
+	}
+	if req.FramedIpAddress != "" {
+		ip := net.ParseIP(req.FramedIpAddress)
+		if ip == nil {
+			return nil, tracerr.New(fmt.Sprintf("ip_address '%v' is not valid IP", req.FramedIpAddress))
+		}
+		if err := rfc2865.FramedIPAddress_Set(packet, ip); err != nil {
+			return nil, tracerr.Wrap(err)
+		}
+	}
+	if len(packet.Attributes) == 0 {
+		return nil, tracerr.New("session identification attributes is empty")
+	}
+	if code == radius.CodeCoARequest {
+		if req.PoolName != "" {
+			if err := rfc2869.FramedPool_SetString(packet, req.PoolName); err != nil {
+				return nil, tracerr.Wrap(err)
+			}
+		}
+		if req.LeaseTimeSec != 0 {
+			if err := rfc2865.SessionTimeout_Set(packet, rfc2865.SessionTimeout(req.LeaseTimeSec)); err != nil {
+				return nil, tracerr.Wrap(err)
+			}
+		}
+	}
+	return packet, nil
+}
+
+// exchangePacket отправляет запрос и ждет ответ, повторяя отправку каждые retry.
+// В отличие от radius.Client запрос кодируется с Message-Authenticator
+func exchangePacket(ctx context.Context, packet *radius.Packet, addr string, retry time.Duration) (*radius.Packet, error) {
+	wire, err := encodePacket(packet)
+	if err != nil {
+		return nil, err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write(wire); err != nil {
+		return nil, err
+	}
+	if retry > 0 {
+		go func() {
+			ticker := time.NewTicker(retry)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					conn.Write(wire)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	incoming := make([]byte, radius.MaxPacketLength)
+	for {
+		n, err := conn.Read(incoming)
+		if err != nil {
+			return nil, err
+		}
+		if !radius.IsAuthenticResponse(incoming[:n], wire, packet.Secret) {
+			continue
+		}
+		//Ответ с невалидным Message-Authenticator, а для Access-Accept/Reject/Challenge - и без него, отбрасывается
+		present, valid := verifyResponseMessageAuthenticator(incoming[:n], wire, packet.Secret)
+		if (present && !valid) || (!present && responseRequiresMessageAuthenticator(radius.Code(incoming[0]))) {
+			reason := "invalid"
+			if !present {
+				reason = "missing"
+			}
+			host, _, _ := net.SplitHostPort(addr)
+			prom.RadMessageAuthenticatorDroppedInc(host, reason)
+			continue
+		}
+		reply, err := radius.Parse(incoming[:n], packet.Secret)
+		if err != nil {
+			continue
+		}
+		return reply, nil
+	}
+}
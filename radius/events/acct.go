@@ -16,4 +16,15 @@ type AcctRequest struct {
 	OutputOctets    int64  `json:"output_octets"`
 	PoolName        string `json:"pool_name"`
 	SessionId       string `json:"session_id"`
+	MultiSessionId  string `json:"multi_session_id"`
+	NasPort         int64  `json:"nas_port"`
+	DelayTime       int64  `json:"delay_time"`
+	EventTimestamp  int64  `json:"event_timestamp"`
+	InputGigawords  int64  `json:"input_gigawords"`
+	OutputGigawords int64  `json:"output_gigawords"`
+	InputPackets    int64  `json:"input_packets"`
+	OutputPackets   int64  `json:"output_packets"`
+
+	//Все атрибуты запроса, заполняется при radius.forward_attributes
+	Attributes map[string][]string `json:"attributes,omitempty"`
 }
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"strings"
 	"time"
+
+	"github.com/meklis/all-ok-radius-server/redback_agent_parsers"
 )
 
 type AuthRequest struct {
@@ -14,20 +17,33 @@ type AuthRequest struct {
 	DeviceMac       string             `json:"device_mac"`
 	DhcpServerName  string             `json:"dhcp_server_name"`
 	DhcpServerId    string             `json:"dhcp_server_id"`
-	AgentOption     *AuthRequestOption `json:"option"`
+	AgentOption     *AuthRequestOption `json:"agent"`
 	FramedIpAddress string             `json:"ip_address"`
 	Class           string             `json:"class_id"`
+	MikrotikRealm   string             `json:"mikrotik_realm,omitempty"`
+	MikrotikHostIp  string             `json:"mikrotik_host_ip,omitempty"`
+
+	//Все атрибуты запроса, заполняется при radius.forward_attributes
+	Attributes map[string][]string `json:"attributes,omitempty"`
 }
 
+// AuthRequestOption - option82 из запроса. CircuitId равен nil, если Circuit-Id не удалось разобрать,
+// исходное значение передается в RawCircuitId. Parser - имя парсера, которым разобран option82
 type AuthRequestOption struct {
-	RemoteId     string `json:"remote_id"`
-	RawCircuitId string `json:"circuit_id"`
+	Parser       string                           `json:"parser,omitempty"`
+	CircuitId    *redback_agent_parsers.CircuitId `json:"circuit_id"`
+	RemoteId     string                           `json:"remote_id"`
+	RemoteIdType string                           `json:"remote_id_type"`
+	RawCircuitId string                           `json:"_raw_circuit_id"`
 }
 
 func (r *AuthRequest) GetHash() string {
 	arrBytes := []byte{}
 	r.Class = ""
-	jsonBytes, _ := json.Marshal(r)
+	//Атрибуты содержат счетчики и идентификаторы, которые меняются от запроса к запросу
+	hashed := *r
+	hashed.Attributes = nil
+	jsonBytes, _ := json.Marshal(hashed)
 	arrBytes = append(arrBytes, jsonBytes...)
 	return fmt.Sprintf("%x", md5.Sum(arrBytes))
 }
@@ -40,16 +56,38 @@ type AuthResponse struct {
 	Status       string    `json:"status"`
 	Error        string    `json:"error"`
 	Class        string    `json:"class_id"`
+	Reject       bool      `json:"reject"`
+	ReplyMessage string    `json:"reply_message"`
+	RateLimit    string    `json:"rate_limit"`
+	AddressList  string    `json:"address_list"`
+
+	Ipv6Address         string `json:"ipv6_address"`
+	DelegatedIpv6Prefix string `json:"delegated_ipv6_prefix"`
+	Ipv6PoolName        string `json:"ipv6_pool_name"`
+
+	Attributes []ReplyAttribute `json:"attributes"`
+}
+
+// ReplyAttribute - дополнительный атрибут ответа, кодируется по словарю (например Mikrotik-Rate-Limit)
+type ReplyAttribute struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
 }
 
 type RadiusResponseType int
 
 const SetPool RadiusResponseType = 1
 const SetIpAddress RadiusResponseType = 2
+const SetReject RadiusResponseType = 3
+const SetIpv6 RadiusResponseType = 4
 
 func (r *AuthResponse) GetRadiusResponseType() RadiusResponseType {
-	if r.IpAddress != "" {
+	if r.Reject {
+		return SetReject
+	} else if r.IpAddress != "" {
 		return SetIpAddress
+	} else if r.PoolName == "" && r.HasIpv6() {
+		return SetIpv6
 	} else {
 		return SetPool
 	}
@@ -57,3 +95,45 @@ func (r *AuthResponse) GetRadiusResponseType() RadiusResponseType {
 func (r *AuthResponse) GetIp() net.IP {
 	return net.ParseIP(r.IpAddress)
 }
+
+// HasIpv6 - ответ содержит IPv6-адрес, делегируемый префикс или IPv6-пул.
+// Может быть задан вместе с IPv4 (dual-stack)
+func (r *AuthResponse) HasIpv6() bool {
+	return r.Ipv6Address != "" || r.DelegatedIpv6Prefix != "" || r.Ipv6PoolName != ""
+}
+
+// GetIpv6Address возвращает ipv6_address без длины префикса как адрес.
+// Если в ipv6_address указана длина префикса, возвращается nil - адрес передается префиксом (GetIpv6Prefix)
+func (r *AuthResponse) GetIpv6Address() (net.IP, error) {
+	if strings.Contains(r.Ipv6Address, "/") {
+		return nil, nil
+	}
+	ip := net.ParseIP(r.Ipv6Address)
+	if ip == nil || ip.To4() != nil {
+		return nil, fmt.Errorf("%v is not IPv6 address", r.Ipv6Address)
+	}
+	return ip, nil
+}
+
+// GetIpv6Prefix возвращает ipv6_address как префикс. Адрес без длины префикса считается /128
+func (r *AuthResponse) GetIpv6Prefix() (*net.IPNet, error) {
+	return parseIpv6Prefix(r.Ipv6Address)
+}
+
+func (r *AuthResponse) GetDelegatedIpv6Prefix() (*net.IPNet, error) {
+	return parseIpv6Prefix(r.DelegatedIpv6Prefix)
+}
+
+func parseIpv6Prefix(s string) (*net.IPNet, error) {
+	if !strings.Contains(s, "/") {
+		s = s + "/128"
+	}
+	ip, prefix, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil, err
+	}
+	if ip.To4() != nil {
+		return nil, fmt.Errorf("%v is not IPv6 prefix", s)
+	}
+	return prefix, nil
+}
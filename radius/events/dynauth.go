@@ -0,0 +1,24 @@
+package events
+
+// DynAuthRequest - запрос на отправку Disconnect-Request или CoA-Request на NAS (RFC 5176)
+type DynAuthRequest struct {
+	NasIp            string `json:"nas_ip"`
+	DynAuthPort      int    `json:"dyn_auth_port"`
+	DeviceMac        string `json:"device_mac"`
+	AcctSessionId    string `json:"acct_session_id"`
+	FramedIpAddress  string `json:"ip_address"`
+	CallingStationId string `json:"calling_station_id"`
+
+	//Атрибуты авторизации, используются только для CoA-Request
+	PoolName     string `json:"pool_name"`
+	LeaseTimeSec int    `json:"lease_time_sec"`
+}
+
+type DynAuthResponse struct {
+	NasIp          string `json:"nas_ip"`
+	Code           string `json:"code"`
+	Ack            bool   `json:"ack"`
+	ErrorCause     int    `json:"error_cause,omitempty"`
+	ErrorCauseName string `json:"error_cause_name,omitempty"`
+	ReplyMessage   string `json:"reply_message,omitempty"`
+}
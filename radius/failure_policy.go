@@ -0,0 +1,99 @@
+package radius
+
+import (
+	"fmt"
+
+	"github.com/meklis/all-ok-radius-server/api"
+	"github.com/meklis/all-ok-radius-server/prom"
+	"github.com/meklis/all-ok-radius-server/radius/events"
+	"github.com/ztrue/tracerr"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+)
+
+const (
+	FailureActionDrop   = "drop"
+	FailureActionReject = "reject"
+	FailureActionPool   = "pool"
+)
+
+// FailurePolicy - что отвечать NAS-у, если API вернуло ошибку или пустой ответ
+type FailurePolicy struct {
+	Action       string `yaml:"action"`
+	ReplyMessage string `yaml:"reply_message"`
+	PoolName     string `yaml:"pool_name"`
+	LeaseTimeSec int    `yaml:"lease_time_sec"`
+}
+
+func (p FailurePolicy) Validate() error {
+	switch p.Action {
+	case "", FailureActionDrop, FailureActionReject:
+		return nil
+	case FailureActionPool:
+		if p.PoolName == "" {
+			return fmt.Errorf("failure policy with action=%v requires pool_name", p.Action)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown failure policy action '%v', must be one of: drop, reject, pool", p.Action)
+}
+
+func (rad *Radius) _handleApiFailure(w radius.ResponseWriter, r *radius.Request, req events.AuthRequest, classId string, reason error) {
+	switch rad.failurePolicy.Action {
+	case FailureActionReject:
+		resp := events.AuthResponse{
+			Reject:       true,
+			ReplyMessage: rad.failurePolicy.ReplyMessage,
+			Status:       "REJECT",
+			Error:        fmt.Sprintf("%v", reason),
+			Class:        classId,
+		}
+		prom.RadRejectsInc(req.NasIp)
+		rad.lg.DebugF("%v %x: api failure, sending reject by policy", r.Code, r.Authenticator)
+		if err := rad._respondAuthReject(resp, w, r); err != nil {
+			prom.ErrorsInc(prom.Critical, "radius")
+			rad.lg.CriticalF("error write response: %v", err.Error())
+			resp.Status = "ERROR"
+			resp.Error = fmt.Sprintf("%v", err)
+		}
+		rad.api.SendPostAuth(api.InitPostAuth(req, resp))
+	case FailureActionPool:
+		resp := events.AuthResponse{
+			PoolName:     rad.failurePolicy.PoolName,
+			LeaseTimeSec: rad.failurePolicy.LeaseTimeSec,
+			Status:       "FALLBACK",
+			Error:        fmt.Sprintf("%v", reason),
+			Class:        classId,
+		}
+		prom.RadRequestsByPoolInc(req.NasIp, resp.PoolName)
+		rad.lg.DebugF("%v %x: api failure, sending fallback pool %v by policy", r.Code, r.Authenticator, resp.PoolName)
+		if err := rad._respondAuthAccept(resp, w, r); err != nil {
+			prom.ErrorsInc(prom.Critical, "radius")
+			rad.lg.CriticalF("error write response: %v", err.Error())
+			resp.Status = "ERROR"
+			resp.Error = fmt.Sprintf("%v", err)
+		}
+		rad.api.SendPostAuth(api.InitPostAuth(req, resp))
+	default:
+		rad.api.SendPostAuth(api.InitPostAuth(req, events.AuthResponse{
+			Status: "ERROR",
+			Error:  fmt.Sprintf("%v", reason),
+			Class:  classId,
+		}))
+	}
+}
+
+func (rad *Radius) _respondAuthReject(response events.AuthResponse, w radius.ResponseWriter, r *radius.Request) error {
+	packet := r.Response(radius.CodeAccessReject)
+	if response.ReplyMessage != "" {
+		if err := rfc2865.ReplyMessage_SetString(packet, response.ReplyMessage); err != nil {
+			prom.ErrorsInc(prom.Error, "radius")
+			rad.lg.ErrorF("error generate reject packet with replyMessage=%v", response.ReplyMessage)
+		}
+	}
+	rad.lg.DebugF("%v %x: reject, replyMessage='%v'", packet.Code, r.Authenticator, response.ReplyMessage)
+	if err := w.Write(packet); err != nil {
+		return tracerr.Wrap(err)
+	}
+	return nil
+}
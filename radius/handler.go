@@ -4,25 +4,49 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 
 	"github.com/meklis/all-ok-radius-server/api"
+	"github.com/meklis/all-ok-radius-server/mikrotik"
 	"github.com/meklis/all-ok-radius-server/prom"
 	"github.com/meklis/all-ok-radius-server/radius/events"
 	"github.com/meklis/all-ok-radius-server/redback"
-	"github.com/meklis/all-ok-radius-server/redback_agent_parsers"
 	"github.com/ztrue/tracerr"
 	"layeh.com/radius"
 	"layeh.com/radius/rfc2865"
 	"layeh.com/radius/rfc2866"
 	"layeh.com/radius/rfc2869"
+	"layeh.com/radius/rfc3162"
+	"layeh.com/radius/rfc6911"
 )
 
+// Delegated-IPv6-Prefix (RFC 4818), в layeh.com/radius нет сгенерированного пакета
+const delegatedIPv6PrefixType radius.Type = 123
+
 func (rad *Radius) handler(w radius.ResponseWriter, r *radius.Request) {
+	if !rad._checkMessageAuthenticator(r) {
+		return
+	}
+	if rad.duplicates != nil && (r.Code == radius.CodeAccessRequest || r.Code == radius.CodeAccountingRequest) {
+		entry, isNew := rad.duplicates.start(r)
+		if !isNew {
+			rad._handleDuplicate(w, r, entry)
+			return
+		}
+		w = &duplicateResponseWriter{ResponseWriter: w, entry: entry}
+		defer rad.duplicates.release(r, entry)
+	}
+	if up := rad.proxy.match(r); up != nil {
+		rad._handleProxy(w, r, up)
+		return
+	}
 	switch r.Code.String() {
 	case "Access-Request":
 		rad._handleAuthRequest(w, r)
 	case "Accounting-Request":
 		rad._handleAccountingRequest(w, r)
+	case "Status-Server":
+		rad._handleStatusServer(w, r)
 	default:
 		rad.lg.CriticalF("Unknown request type from radius-client - %v", r.Code.String())
 	}
@@ -49,25 +73,44 @@ func (rad *Radius) _handleAuthRequest(w radius.ResponseWriter, r *radius.Request
 		return
 	}
 	req.Class = classId
+	nas := addrIP(r.RemoteAddr).String()
+	if limit, started := rad.rateLimit.check(nas, req.DeviceMac); limit != "" {
+		rad._handleRateLimited(w, r, req, nas, limit, started)
+		return
+	}
 	resp, err := rad._handlerProccessApi(req)
 	if err != nil {
 		prom.ErrorsInc(prom.Critical, "radius")
 		rad.lg.CriticalF("error get answer from api's: %v", err.Error())
 		rad.lg.DebugF(tracerr.Sprint(err))
-		rad.api.SendPostAuth(api.InitPostAuth(req, events.AuthResponse{
-			Status: "ERROR",
-			Error:  fmt.Sprintf("%v", err),
-			Class:  classId,
-		}))
+		rad._handleApiFailure(w, r, req, classId, err)
 		return
-	} else if resp.IpAddress == "" && resp.PoolName == "" {
+	} else if !resp.Reject && resp.IpAddress == "" && resp.PoolName == "" && !resp.HasIpv6() {
+		err = errors.New("pool_name and ip_address is empty")
 		prom.ErrorsInc(prom.Critical, "radius")
-		rad.lg.CriticalF("error get answer from api's: pool_name and ip_address is empty")
-		rad.api.SendPostAuth(api.InitPostAuth(req, events.AuthResponse{
-			Status: "ERROR",
-			Error:  fmt.Sprintf("%v", err),
-			Class:  classId,
-		}))
+		rad.lg.CriticalF("error get answer from api's: %v", err.Error())
+		rad._handleApiFailure(w, r, req, classId, err)
+		return
+	}
+	resp.Class = classId
+	if resp.Reject {
+		prom.RadRejectsInc(req.NasIp)
+		prom.RadDetailedRequest(req.NasIp, req.DhcpServerName, req.DeviceMac, "reject")
+		rad.lg.DebugF("%v %x: response from api - reject, replyMessage=%v", r.Code, r.Authenticator, resp.ReplyMessage)
+		if err := rad._respondAuthReject(*resp, w, r); err != nil {
+			rad.api.SendPostAuth(api.InitPostAuth(req, events.AuthResponse{
+				Status: "ERROR",
+				Error:  fmt.Sprintf("%v", err),
+				Class:  classId,
+			}))
+			prom.ErrorsInc(prom.Critical, "radius")
+			rad.lg.CriticalF("error write response: %v", err.Error())
+			return
+		}
+		if resp.Status == "" {
+			resp.Status = "REJECT"
+		}
+		rad.api.SendPostAuth(api.InitPostAuth(req, *resp))
 		return
 	}
 	prom.RadRequestsInc(req.NasIp)
@@ -80,9 +123,14 @@ func (rad *Radius) _handleAuthRequest(w radius.ResponseWriter, r *radius.Request
 		prom.RadRequestsIpAddressInc(req.NasIp)
 		prom.RadDetailedRequest(req.NasIp, req.DhcpServerName, req.DeviceMac, "ip")
 	}
+	if resp.HasIpv6() {
+		prom.RadDetailedRequest(req.NasIp, req.DhcpServerName, req.DeviceMac, "ipv6")
+	}
 
-	resp.Class = classId
 	rad.lg.DebugF("%v %x: response from api - poolName=%v ipAddr=%v leaseTimeSec=%v", r.Code, r.Authenticator, resp.PoolName, resp.IpAddress, resp.LeaseTimeSec)
+	if resp.HasIpv6() {
+		rad.lg.DebugF("%v %x: response from api - ipv6Addr=%v delegatedIpv6Prefix=%v ipv6PoolName=%v", r.Code, r.Authenticator, resp.Ipv6Address, resp.DelegatedIpv6Prefix, resp.Ipv6PoolName)
+	}
 	err = rad._respondAuthAccept(*resp, w, r)
 
 	if err != nil {
@@ -106,14 +154,16 @@ func (rad *Radius) _parseAuthRequest(r *radius.Request) (events.AuthRequest, err
 	dhcpServerName := rfc2865.CalledStationID_GetString(r.Packet)
 	dhcpServerId := rfc2865.CallingStationID_GetString(r.Packet)
 	rad.lg.DebugF("%v %x: nasName=%v, nasIpAddr=%v, deviceMac=%v, dhcpServerName=%v, dhcpServerId=%v", r.Code.String(), r.Authenticator, nasName, nasIpAddr, deviceMAC, dhcpServerName, dhcpServerId)
-	agent := new(events.AuthRequestOption)
-	remoteId := redback_agent_parsers.ParseRemoteId(redback.AgentRemoteID_Get(r.Packet))
-
-	agent.RemoteId = remoteId
-	rad.lg.DebugF("%v %x: agentRemoteId=%v", r.Code.String(), r.Authenticator, agent.RemoteId)
-	if bts := redback.AgentCircuitID_Get(r.Packet); len(bts) > 2 {
-		agent.RawCircuitId = fmt.Sprintf("%X", bts[2:])
-
+	nasIps := []net.IP{addrIP(r.RemoteAddr)}
+	if nasIp, err := rfc2865.NASIPAddress_Lookup(r.Packet); err == nil {
+		nasIps = append(nasIps, nasIp)
+	}
+	agent, err := rad.option82.Parse(redback.AgentCircuitID_Get(r.Packet), redback.AgentRemoteID_Get(r.Packet), nasIps, nasName, dhcpServerName)
+	if err != nil {
+		rad.lg.DebugF("%v %x: error parse circuit-id %v: %v", r.Code.String(), r.Authenticator, agent.RawCircuitId, err.Error())
+	}
+	if agent != nil {
+		rad.lg.DebugF("%v %x: agentParser=%v, agentRemoteId=%v, agentCircuitId=%v", r.Code.String(), r.Authenticator, agent.Parser, agent.RemoteId, agent.RawCircuitId)
 	}
 	request := events.AuthRequest{
 		NasIp:          nasIpAddr,
@@ -122,6 +172,13 @@ func (rad *Radius) _parseAuthRequest(r *radius.Request) (events.AuthRequest, err
 		DhcpServerName: dhcpServerName,
 		DhcpServerId:   dhcpServerId,
 		AgentOption:    agent,
+		MikrotikRealm:  mikrotik.MikrotikRealm_GetString(r.Packet),
+	}
+	if hostIp, err := mikrotik.MikrotikHostIP_Lookup(r.Packet); err == nil {
+		request.MikrotikHostIp = hostIp.String()
+	}
+	if rad.forwardAttrs {
+		request.Attributes = rad.dictionary.Decode(r.Packet)
 	}
 	return request, nil
 }
@@ -130,9 +187,20 @@ func (rad *Radius) _handleAccountingRequest(w radius.ResponseWriter, r *radius.R
 	req, _ := rad._parseAccountingRequest(r)
 	prom.RadAcctRequestsInc(req.NasIp, req.DhcpServerName)
 
-	rad.api.SendAcct(&req)
-	r.Code = radius.CodeAccountingResponse
-	w.Write(r.Packet)
+	if err := rad.api.SendAcct(&req); err != nil {
+		prom.ErrorsInc(prom.Error, "radius")
+		rad.lg.ErrorF("%v %x: accounting is not accepted, response will not be sent: %v", r.Code, r.Authenticator, err.Error())
+		return
+	}
+	//RFC 2866 - в ответе не должно быть атрибутов, кроме Proxy-State
+	resp := r.Response(radius.CodeAccountingResponse)
+	for _, proxyState := range r.Attributes[rfc2865.ProxyState_Type] {
+		resp.Add(rfc2865.ProxyState_Type, proxyState)
+	}
+	if err := w.Write(resp); err != nil {
+		prom.ErrorsInc(prom.Error, "radius")
+		rad.lg.ErrorF("error write response: %v", err.Error())
+	}
 }
 
 func (rad *Radius) _parseAccountingRequest(r *radius.Request) (events.AcctRequest, error) {
@@ -148,8 +216,15 @@ func (rad *Radius) _parseAccountingRequest(r *radius.Request) (events.AcctReques
 	statusType := rfc2866.AcctStatusType_Strings[rfc2866.AcctStatusType_Get(r.Packet)]
 	sessionTime := int64(rfc2866.AcctSessionTime_Get(r.Packet))
 	terminateCause := rfc2866.AcctTerminateCause_Strings[rfc2866.AcctTerminateCause_Get(r.Packet)]
-	inOctets := int64(rfc2866.AcctInputOctets_Get(r.Packet))
-	outOctets := int64(rfc2866.AcctOutputOctets_Get(r.Packet))
+	inGigawords := int64(rfc2869.AcctInputGigawords_Get(r.Packet))
+	outGigawords := int64(rfc2869.AcctOutputGigawords_Get(r.Packet))
+	//Счетчики октетов 32-битные, переполнения передаются в Acct-*-Gigawords (RFC 2869)
+	inOctets := inGigawords<<32 | int64(rfc2866.AcctInputOctets_Get(r.Packet))
+	outOctets := outGigawords<<32 | int64(rfc2866.AcctOutputOctets_Get(r.Packet))
+	var eventTimestamp int64
+	if ts, err := rfc2869.EventTimestamp_Lookup(r.Packet); err == nil {
+		eventTimestamp = ts.Unix()
+	}
 
 	request := events.AcctRequest{
 		NasIp:           nasIpAddr,
@@ -166,6 +241,18 @@ func (rad *Radius) _parseAccountingRequest(r *radius.Request) (events.AcctReques
 		InputOctets:     inOctets,
 		OutputOctets:    outOctets,
 		PoolName:        poolName,
+		SessionId:       rfc2866.AcctSessionID_GetString(r.Packet),
+		MultiSessionId:  rfc2866.AcctMultiSessionID_GetString(r.Packet),
+		NasPort:         int64(rfc2865.NASPort_Get(r.Packet)),
+		DelayTime:       int64(rfc2866.AcctDelayTime_Get(r.Packet)),
+		EventTimestamp:  eventTimestamp,
+		InputGigawords:  inGigawords,
+		OutputGigawords: outGigawords,
+		InputPackets:    int64(rfc2866.AcctInputPackets_Get(r.Packet)),
+		OutputPackets:   int64(rfc2866.AcctOutputPackets_Get(r.Packet)),
+	}
+	if rad.forwardAttrs {
+		request.Attributes = rad.dictionary.Decode(r.Packet)
 	}
 	d, _ := json.Marshal(&request)
 	rad.lg.DebugF("%v %x: %v", r.Code.String(), r.Authenticator, string(d))
@@ -192,18 +279,36 @@ func (rad *Radius) _respondAuthAccept(response events.AuthResponse, w radius.Res
 			prom.ErrorsInc(prom.Error, "radius")
 			rad.lg.ErrorF("error generate response packet for ip=%v", response.IpAddress)
 		}
+	case events.SetIpv6:
 	default:
 		rad.lg.ErrorF("unknown type of response set with id: %v", response.GetRadiusResponseType())
 		prom.ErrorsInc(prom.Error, "radius")
 		return errors.New(fmt.Sprintf("unknown type of response set with id: %v", response.GetRadiusResponseType()))
 	}
 
+	if response.HasIpv6() {
+		rad._setIpv6Attributes(r.Packet, response)
+	}
+
 	if response.LeaseTimeSec != 0 {
 		if err := rfc2865.SessionTimeout_Set(r.Packet, rfc2865.SessionTimeout(response.LeaseTimeSec)); err != nil {
 			prom.ErrorsInc(prom.Error, "radius")
 			rad.lg.ErrorF("error set response SessionTimeOut=%v", response.LeaseTimeSec)
 		}
 	}
+	if response.RateLimit != "" {
+		if err := mikrotik.MikrotikRateLimit_SetString(r.Packet, response.RateLimit); err != nil {
+			prom.ErrorsInc(prom.Error, "radius")
+			rad.lg.ErrorF("error set response Mikrotik-Rate-Limit=%v", response.RateLimit)
+		}
+	}
+	if response.AddressList != "" {
+		if err := mikrotik.MikrotikAddressList_SetString(r.Packet, response.AddressList); err != nil {
+			prom.ErrorsInc(prom.Error, "radius")
+			rad.lg.ErrorF("error set response Mikrotik-Address-List=%v", response.AddressList)
+		}
+	}
+	rad._setReplyAttributes(r.Packet, response.Attributes)
 	r.Code = radius.CodeAccessAccept
 	rad.lg.DebugF("%v %x: ipAddress='%v', poolName='%v', lease_time='%v'", r.Code, r.Authenticator, response.IpAddress, response.PoolName, response.LeaseTimeSec)
 
@@ -213,3 +318,53 @@ func (rad *Radius) _respondAuthAccept(response events.AuthResponse, w radius.Res
 	}
 	return nil
 }
+
+func (rad *Radius) _setReplyAttributes(p *radius.Packet, attributes []events.ReplyAttribute) {
+	for _, attr := range attributes {
+		if err := rad.dictionary.Encode(p, attr); err != nil {
+			prom.ErrorsInc(prom.Error, "radius")
+			rad.lg.ErrorF("error set response attribute: %v", err.Error())
+		}
+	}
+}
+
+// _setIpv6Attributes добавляет в ответ Framed-IPv6-Address (RFC 6911) или Framed-IPv6-Prefix, Framed-IPv6-Pool (RFC 3162)
+// и Delegated-IPv6-Prefix (RFC 4818). ipv6_address без длины префикса передается в Framed-IPv6-Address
+func (rad *Radius) _setIpv6Attributes(p *radius.Packet, response events.AuthResponse) {
+	if response.Ipv6Address != "" {
+		ip, err := response.GetIpv6Address()
+		switch {
+		case err != nil:
+		case ip != nil:
+			err = rfc6911.FramedIPv6Address_Set(p, ip)
+		default:
+			var prefix *net.IPNet
+			if prefix, err = response.GetIpv6Prefix(); err == nil {
+				err = rfc3162.FramedIPv6Prefix_Set(p, prefix)
+			}
+		}
+		if err != nil {
+			prom.ErrorsInc(prom.Error, "radius")
+			rad.lg.ErrorF("error generate response packet for ipv6=%v: %v", response.Ipv6Address, err)
+		}
+	}
+	if response.Ipv6PoolName != "" {
+		if err := rfc3162.FramedIPv6Pool_SetString(p, response.Ipv6PoolName); err != nil {
+			prom.ErrorsInc(prom.Error, "radius")
+			rad.lg.ErrorF("error generate response packet for ipv6 pool=%v", response.Ipv6PoolName)
+		}
+	}
+	if response.DelegatedIpv6Prefix != "" {
+		prefix, err := response.GetDelegatedIpv6Prefix()
+		var attr radius.Attribute
+		if err == nil {
+			attr, err = radius.NewIPv6Prefix(prefix)
+		}
+		if err != nil {
+			prom.ErrorsInc(prom.Error, "radius")
+			rad.lg.ErrorF("error generate response packet for delegated ipv6 prefix=%v: %v", response.DelegatedIpv6Prefix, err)
+			return
+		}
+		p.Set(delegatedIPv6PrefixType, attr)
+	}
+}
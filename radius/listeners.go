@@ -0,0 +1,67 @@
+package radius
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/meklis/all-ok-radius-server/prom"
+	"layeh.com/radius"
+)
+
+const (
+	RoleAuth = "auth"
+	RoleAcct = "acct"
+	RoleBoth = "both"
+)
+
+// Listener - адрес, на котором радиус принимает запросы.
+// Role определяет какие запросы принимаются: auth (Access-Request), acct (Accounting-Request) или both
+type Listener struct {
+	Name       string `yaml:"name"`
+	ListenAddr string `yaml:"listen_addr"`
+	Proto      string `yaml:"proto"`
+	Role       string `yaml:"role"`
+}
+
+func (l Listener) Validate() error {
+	if l.ListenAddr == "" {
+		return fmt.Errorf("listener %v has empty listen_addr", l.Name)
+	}
+	switch l.Role {
+	case "", RoleAuth, RoleAcct, RoleBoth:
+	default:
+		return fmt.Errorf("unknown role '%v' for listener %v, must be one of: auth, acct, both", l.Role, l.ListenAddr)
+	}
+	if _, err := parseProto(l.Proto); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (l Listener) accepts(code radius.Code) bool {
+	switch l.Role {
+	case RoleAuth:
+		return code == radius.CodeAccessRequest || code == radius.CodeStatusServer
+	case RoleAcct:
+		return code == radius.CodeAccountingRequest || code == radius.CodeStatusServer
+	}
+	return true
+}
+
+func listenerFromContext(ctx context.Context) (Listener, bool) {
+	l, ok := ctx.Value(listenerCtxKey).(Listener)
+	return l, ok
+}
+
+// listenerHandler считает запросы по листенеру и отбрасывает запросы, не соответствующие его роли
+func (rad *Radius) listenerHandler(l Listener) radius.Handler {
+	return radius.HandlerFunc(func(w radius.ResponseWriter, r *radius.Request) {
+		prom.RadListenerRequestsInc(l.Name, r.Code.String())
+		if !l.accepts(r.Code) {
+			prom.RadListenerDroppedInc(l.Name, r.Code.String())
+			rad.lg.WarningF("%v %x: request from %v dropped, listener %v accepts only %v", r.Code, r.Authenticator, r.RemoteAddr, l.Name, l.Role)
+			return
+		}
+		rad.handler(w, r.WithContext(context.WithValue(r.Context(), listenerCtxKey, l)))
+	})
+}
@@ -0,0 +1,179 @@
+package radius
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/meklis/all-ok-radius-server/prom"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2869"
+)
+
+const messageAuthenticatorLen = 2 + md5.Size
+
+// findMessageAuthenticator возвращает смещение значения Message-Authenticator в пакете или -1
+func findMessageAuthenticator(raw []byte) int {
+	offset := 20
+	for offset+2 <= len(raw) {
+		typ, length := raw[offset], int(raw[offset+1])
+		if length < 2 || offset+length > len(raw) {
+			return -1
+		}
+		if radius.Type(typ) == rfc2869.MessageAuthenticator_Type && length == messageAuthenticatorLen {
+			return offset + 2
+		}
+		offset += length
+	}
+	return -1
+}
+
+func calcMessageAuthenticator(raw []byte, maOffset int, authenticator []byte, secret []byte) []byte {
+	buff := make([]byte, len(raw))
+	copy(buff, raw)
+	copy(buff[4:20], authenticator)
+	for i := maOffset; i < maOffset+md5.Size; i++ {
+		buff[i] = 0
+	}
+	mac := hmac.New(md5.New, secret)
+	mac.Write(buff)
+	return mac.Sum(nil)
+}
+
+// Для Accounting-Request, Disconnect-Request и CoA-Request Message-Authenticator считается с нулевым аутентификатором
+func requestAuthenticatorForMA(raw []byte) []byte {
+	switch radius.Code(raw[0]) {
+	case radius.CodeAccountingRequest, radius.CodeDisconnectRequest, radius.CodeCoARequest:
+		return make([]byte, 16)
+	}
+	return raw[4:20]
+}
+
+// verifyMessageAuthenticator проверяет Message-Authenticator во входящем запросе.
+// Возвращает present=false, если атрибут отсутствует
+func verifyMessageAuthenticator(raw []byte, secret []byte) (present bool, valid bool) {
+	return checkMessageAuthenticator(raw, requestAuthenticatorForMA(raw), secret)
+}
+
+// verifyResponseMessageAuthenticator проверяет Message-Authenticator в ответе на запрос request:
+// в ответе он считается с аутентификатором запроса (RFC 3579, 3.2)
+func verifyResponseMessageAuthenticator(raw []byte, request []byte, secret []byte) (present bool, valid bool) {
+	return checkMessageAuthenticator(raw, request[4:20], secret)
+}
+
+// responseRequiresMessageAuthenticator - ответы на Access-Request без Message-Authenticator отбрасываются (BlastRADIUS)
+func responseRequiresMessageAuthenticator(code radius.Code) bool {
+	switch code {
+	case radius.CodeAccessAccept, radius.CodeAccessReject, radius.CodeAccessChallenge:
+		return true
+	}
+	return false
+}
+
+func checkMessageAuthenticator(raw []byte, authenticator []byte, secret []byte) (present bool, valid bool) {
+	offset := findMessageAuthenticator(raw)
+	if offset < 0 {
+		return false, false
+	}
+	expected := calcMessageAuthenticator(raw, offset, authenticator, secret)
+	return true, hmac.Equal(expected, raw[offset:offset+md5.Size])
+}
+
+// encodePacket кодирует пакет так же как radius.Packet.Encode, но первым атрибутом всегда
+// добавляет Message-Authenticator (защита от BlastRADIUS)
+func encodePacket(p *radius.Packet) ([]byte, error) {
+	types := make([]int, 0, len(p.Attributes))
+	attrsSize := 0
+	for typ, attrs := range p.Attributes {
+		if typ < 1 || typ > 255 || typ == rfc2869.MessageAuthenticator_Type {
+			continue
+		}
+		types = append(types, int(typ))
+		for _, attr := range attrs {
+			if len(attr) > 253 {
+				return nil, errors.New("invalid packet attribute length")
+			}
+			attrsSize += 2 + len(attr)
+		}
+	}
+	sort.Ints(types)
+
+	size := 20 + messageAuthenticatorLen + attrsSize
+	if size > radius.MaxPacketLength {
+		return nil, errors.New("encoded packet is too long")
+	}
+	b := make([]byte, size)
+	b[0] = byte(p.Code)
+	b[1] = p.Identifier
+	binary.BigEndian.PutUint16(b[2:4], uint16(size))
+	b[20] = byte(rfc2869.MessageAuthenticator_Type)
+	b[21] = messageAuthenticatorLen
+	offset := 20 + messageAuthenticatorLen
+	for _, typ := range types {
+		for _, attr := range p.Attributes[radius.Type(typ)] {
+			b[offset] = byte(typ)
+			b[offset+1] = byte(2 + len(attr))
+			copy(b[offset+2:], attr)
+			offset += 2 + len(attr)
+		}
+	}
+
+	switch p.Code {
+	case radius.CodeAccessRequest, radius.CodeStatusServer:
+		copy(b[4:20], p.Authenticator[:])
+		copy(b[22:38], calcMessageAuthenticator(b, 22, p.Authenticator[:], p.Secret))
+	case radius.CodeAccountingRequest, radius.CodeDisconnectRequest, radius.CodeCoARequest:
+		var nul [16]byte
+		copy(b[22:38], calcMessageAuthenticator(b, 22, nul[:], p.Secret))
+		signPacket(b, nul[:], p.Secret)
+	case radius.CodeAccessAccept, radius.CodeAccessReject, radius.CodeAccessChallenge, radius.CodeAccountingResponse,
+		radius.CodeDisconnectACK, radius.CodeDisconnectNAK, radius.CodeCoAACK, radius.CodeCoANAK:
+		copy(b[22:38], calcMessageAuthenticator(b, 22, p.Authenticator[:], p.Secret))
+		signPacket(b, p.Authenticator[:], p.Secret)
+	default:
+		return nil, errors.New("radius: unknown Packet Code")
+	}
+	return b, nil
+}
+
+func signPacket(b []byte, authenticator []byte, secret []byte) {
+	hash := md5.New()
+	hash.Write(b[:4])
+	hash.Write(authenticator)
+	hash.Write(b[20:])
+	hash.Write(secret)
+	hash.Sum(b[4:4:20])
+}
+
+// _checkMessageAuthenticator отбрасывает запросы с невалидным Message-Authenticator, а также
+// запросы без него, если он обязателен (Status-Server по RFC 5997 или require_message_authenticator у клиента)
+func (rad *Radius) _checkMessageAuthenticator(r *radius.Request) bool {
+	raw, ok := rawPacketFromContext(r.Context())
+	if !ok {
+		return true
+	}
+	host := addrIP(r.RemoteAddr).String()
+	present, valid := verifyMessageAuthenticator(raw, r.Secret)
+	if present && !valid {
+		prom.RadMessageAuthenticatorDroppedInc(host, "invalid")
+		rad.lg.WarningF("%v %x: invalid Message-Authenticator from %v, packet dropped", r.Code, r.Authenticator, r.RemoteAddr)
+		return false
+	}
+	if present {
+		return true
+	}
+	required := r.Code == radius.CodeStatusServer
+	if r.Code == radius.CodeAccessRequest {
+		if client, ok := rad.requestClient(r); ok && client.RequireMessageAuthenticator {
+			required = true
+		}
+	}
+	if required {
+		prom.RadMessageAuthenticatorDroppedInc(host, "missing")
+		rad.lg.WarningF("%v %x: Message-Authenticator is required but missing from %v, packet dropped", r.Code, r.Authenticator, r.RemoteAddr)
+		return false
+	}
+	return true
+}
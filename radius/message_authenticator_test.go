@@ -0,0 +1,185 @@
+package radius
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/meklis/all-ok-radius-server/logger"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+	"layeh.com/radius/rfc2869"
+)
+
+var testSecret = []byte("secret")
+
+func testRequest(t *testing.T, code radius.Code) *radius.Packet {
+	t.Helper()
+	p := radius.New(code, testSecret)
+	if err := rfc2865.UserName_SetString(p, "00:01:02:03:04:05"); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestEncodePacketResponseMessageAuthenticator(t *testing.T) {
+	tests := []struct {
+		name string
+		code radius.Code
+	}{
+		{"access-accept", radius.CodeAccessAccept},
+		{"access-reject", radius.CodeAccessReject},
+		{"access-challenge", radius.CodeAccessChallenge},
+		{"accounting-response", radius.CodeAccountingResponse},
+		{"coa-ack", radius.CodeCoAACK},
+		{"disconnect-nak", radius.CodeDisconnectNAK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := testRequest(t, radius.CodeAccessRequest)
+			requestRaw, err := encodePacket(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			response := request.Response(tt.code)
+			if err := rfc2865.ReplyMessage_SetString(response, "ok"); err != nil {
+				t.Fatal(err)
+			}
+			raw, err := encodePacket(response)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if radius.Type(raw[20]) != rfc2869.MessageAuthenticator_Type || raw[21] != messageAuthenticatorLen {
+				t.Fatalf("Message-Authenticator is not the first attribute: type %v, length %v", raw[20], raw[21])
+			}
+			if offset := findMessageAuthenticator(raw); offset != 22 {
+				t.Fatalf("Message-Authenticator value offset %v, expected 22", offset)
+			}
+			if !radius.IsAuthenticResponse(raw, requestRaw, testSecret) {
+				t.Fatal("response authenticator is not valid")
+			}
+			if present, valid := verifyResponseMessageAuthenticator(raw, requestRaw, testSecret); !present || !valid {
+				t.Fatalf("Message-Authenticator present=%v valid=%v", present, valid)
+			}
+			if _, valid := verifyResponseMessageAuthenticator(raw, requestRaw, []byte("other")); valid {
+				t.Fatal("Message-Authenticator is valid with wrong secret")
+			}
+		})
+	}
+}
+
+func TestEncodePacketRequestMessageAuthenticator(t *testing.T) {
+	tests := []struct {
+		name string
+		code radius.Code
+	}{
+		{"access-request", radius.CodeAccessRequest},
+		{"status-server", radius.CodeStatusServer},
+		{"accounting-request", radius.CodeAccountingRequest},
+		{"coa-request", radius.CodeCoARequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := encodePacket(testRequest(t, tt.code))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if offset := findMessageAuthenticator(raw); offset != 22 {
+				t.Fatalf("Message-Authenticator value offset %v, expected 22", offset)
+			}
+			if present, valid := verifyMessageAuthenticator(raw, testSecret); !present || !valid {
+				t.Fatalf("Message-Authenticator present=%v valid=%v", present, valid)
+			}
+			if _, err := radius.Parse(raw, testSecret); err != nil {
+				t.Fatalf("encoded packet is not parsed: %v", err)
+			}
+		})
+	}
+}
+
+func TestFindMessageAuthenticatorMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []byte
+	}{
+		{"header only", make([]byte, 20)},
+		{"truncated attribute header", append(make([]byte, 20), 80)},
+		{"attribute longer than packet", append(make([]byte, 20), 80, 18, 0, 0)},
+		{"zero attribute length", append(make([]byte, 20), 1, 0, 80, 18)},
+		{"wrong Message-Authenticator length", append(make([]byte, 20), 80, 4, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if offset := findMessageAuthenticator(tt.raw); offset != -1 {
+				t.Fatalf("offset %v, expected -1", offset)
+			}
+		})
+	}
+}
+
+func TestCheckMessageAuthenticator(t *testing.T) {
+	const (
+		maValid   = "valid"
+		maInvalid = "invalid"
+		maMissing = "missing"
+	)
+	tests := []struct {
+		name    string
+		code    radius.Code
+		ma      string
+		require bool
+		want    bool
+	}{
+		{"access-request with valid MA", radius.CodeAccessRequest, maValid, false, true},
+		{"access-request with invalid MA", radius.CodeAccessRequest, maInvalid, false, false},
+		{"access-request without MA", radius.CodeAccessRequest, maMissing, false, true},
+		{"access-request with valid MA, required", radius.CodeAccessRequest, maValid, true, true},
+		{"access-request with invalid MA, required", radius.CodeAccessRequest, maInvalid, true, false},
+		{"access-request without MA, required", radius.CodeAccessRequest, maMissing, true, false},
+		{"accounting-request without MA, required", radius.CodeAccountingRequest, maMissing, true, true},
+		{"accounting-request with invalid MA", radius.CodeAccountingRequest, maInvalid, false, false},
+		{"status-server with valid MA", radius.CodeStatusServer, maValid, false, true},
+		{"status-server with invalid MA", radius.CodeStatusServer, maInvalid, false, false},
+		{"status-server without MA", radius.CodeStatusServer, maMissing, false, false},
+	}
+	lg, _ := logger.New("test", 0, ioutil.Discard)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients, err := NewClients([]Client{{Address: "10.0.0.1", Secret: string(testSecret), RequireMessageAuthenticator: tt.require}}, "", lg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rad := Init().SetLogger(lg).SetClients(clients)
+
+			packet := testRequest(t, tt.code)
+			var raw []byte
+			if tt.ma == maMissing {
+				raw, err = packet.Encode()
+			} else {
+				raw, err = encodePacket(packet)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.ma == maInvalid {
+				raw[22] ^= 0xff
+				if tt.code != radius.CodeAccessRequest && tt.code != radius.CodeStatusServer {
+					signPacket(raw, make([]byte, 16), testSecret)
+				}
+			}
+			parsed, err := radius.Parse(raw, testSecret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := (&radius.Request{
+				RemoteAddr: &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1812},
+				Packet:     parsed,
+			}).WithContext(context.WithValue(context.Background(), rawPacketCtxKey, raw))
+
+			if got := rad._checkMessageAuthenticator(r); got != tt.want {
+				t.Fatalf("_checkMessageAuthenticator() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
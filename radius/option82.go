@@ -0,0 +1,190 @@
+package radius
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/meklis/all-ok-radius-server/radius/events"
+	"github.com/meklis/all-ok-radius-server/redback_agent_parsers"
+)
+
+// Option82Auto - автоопределение формата option82 по содержимому Circuit-Id
+const Option82Auto = "auto"
+
+// Option82Config - выбор парсера option82 по NAS или dhcp-серверу.
+// default - парсер для запросов, не попавших ни под одно правило (по умолчанию auto).
+// remote_id_format - вывод Remote-Id: auto (по типу подопции), mac, ascii или hex.
+// decoders - парсеры, описанные шаблонами, регистрируются вместе со встроенными
+type Option82Config struct {
+	Default        string                                 `yaml:"default"`
+	RemoteIdFormat string                                 `yaml:"remote_id_format"`
+	Rules          []Option82Rule                         `yaml:"rules"`
+	Decoders       []redback_agent_parsers.TemplateConfig `yaml:"decoders"`
+}
+
+// Option82Rule - парсер для запросов от NAS-ов (IP или подсеть), с указанными NAS-Identifier или именами dhcp-серверов.
+// Все заполненные условия должны совпасть, внутри списка достаточно одного совпадения.
+// remote_id_format, если задан, заменяет общий radius.option82.remote_id_format
+type Option82Rule struct {
+	Parser         string   `yaml:"parser"`
+	RemoteIdFormat string   `yaml:"remote_id_format"`
+	Nas            []string `yaml:"nas"`
+	NasNames       []string `yaml:"nas_names"`
+	DhcpServers    []string `yaml:"dhcp_servers"`
+}
+
+type option82Rule struct {
+	conf   Option82Rule
+	nas    []*net.IPNet
+	parser redback_agent_parsers.Parser
+}
+
+// Option82 - таблица правил выбора парсера option82
+type Option82 struct {
+	rules          []option82Rule
+	defaultParser  redback_agent_parsers.Parser
+	remoteIdFormat string
+}
+
+// Validate проверяет правила и парсеры из конфигурации, не регистрируя их
+func (c Option82Config) Validate() error {
+	decoders := make(map[string]bool)
+	for _, decoderConf := range c.Decoders {
+		if _, err := redback_agent_parsers.NewTemplateParser(decoderConf); err != nil {
+			return err
+		}
+		decoders[decoderConf.Name] = true
+	}
+	checkParser := func(name string) error {
+		if decoders[name] {
+			return nil
+		}
+		_, err := getOption82Parser(name)
+		return err
+	}
+	if err := checkParser(c.Default); err != nil {
+		return err
+	}
+	if !redback_agent_parsers.ValidRemoteIdFormat(c.RemoteIdFormat) {
+		return fmt.Errorf("unknown option82 remote_id_format '%v', must be one of: auto, mac, ascii, hex", c.RemoteIdFormat)
+	}
+	for _, ruleConf := range c.Rules {
+		if err := checkParser(ruleConf.Parser); err != nil {
+			return err
+		}
+		if !redback_agent_parsers.ValidRemoteIdFormat(ruleConf.RemoteIdFormat) {
+			return fmt.Errorf("unknown option82 remote_id_format '%v' for parser %v", ruleConf.RemoteIdFormat, ruleConf.Parser)
+		}
+		for _, nas := range ruleConf.Nas {
+			if _, err := parseClientAddress(nas); err != nil {
+				return fmt.Errorf("option82 rule for parser %v: %v", ruleConf.Parser, err)
+			}
+		}
+	}
+	return nil
+}
+
+func NewOption82(conf Option82Config) (*Option82, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	o := new(Option82)
+	for _, decoderConf := range conf.Decoders {
+		decoder, err := redback_agent_parsers.NewTemplateParser(decoderConf)
+		if err != nil {
+			return nil, err
+		}
+		redback_agent_parsers.Register(decoder)
+	}
+	var err error
+	if o.defaultParser, err = getOption82Parser(conf.Default); err != nil {
+		return nil, err
+	}
+	o.remoteIdFormat = conf.RemoteIdFormat
+	for _, ruleConf := range conf.Rules {
+		rule := option82Rule{conf: ruleConf}
+		if rule.parser, err = getOption82Parser(ruleConf.Parser); err != nil {
+			return nil, err
+		}
+		for _, nas := range ruleConf.Nas {
+			network, err := parseClientAddress(nas)
+			if err != nil {
+				return nil, fmt.Errorf("option82 rule for parser %v: %v", ruleConf.Parser, err)
+			}
+			rule.nas = append(rule.nas, network)
+		}
+		o.rules = append(o.rules, rule)
+	}
+	return o, nil
+}
+
+// getOption82Parser возвращает парсер по имени, для auto - nil
+func getOption82Parser(name string) (redback_agent_parsers.Parser, error) {
+	if name == "" || name == Option82Auto {
+		return nil, nil
+	}
+	parser, ok := redback_agent_parsers.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown option82 parser '%v'", name)
+	}
+	return parser, nil
+}
+
+// match возвращает парсер и формат Remote-Id первого совпавшего правила или значения по умолчанию.
+// Парсер nil - автоопределение
+func (o *Option82) match(nasIps []net.IP, nasName, dhcpServerName string) (redback_agent_parsers.Parser, string) {
+	if o == nil {
+		return nil, ""
+	}
+	for _, rule := range o.rules {
+		if len(rule.nas) != 0 && !containsIP(rule.nas, nasIps) {
+			continue
+		}
+		if len(rule.conf.NasNames) != 0 && !containsFold(rule.conf.NasNames, nasName) {
+			continue
+		}
+		if len(rule.conf.DhcpServers) != 0 && !containsFold(rule.conf.DhcpServers, dhcpServerName) {
+			continue
+		}
+		remoteIdFormat := rule.conf.RemoteIdFormat
+		if remoteIdFormat == "" {
+			remoteIdFormat = o.remoteIdFormat
+		}
+		return rule.parser, remoteIdFormat
+	}
+	return o.defaultParser, o.remoteIdFormat
+}
+
+// Parse разбирает option82 парсером, выбранным по NAS и dhcp-серверу.
+// Возвращает nil, если в запросе нет ни Circuit-Id, ни Remote-Id
+func (o *Option82) Parse(circuitIdBytes, remoteIdBytes []byte, nasIps []net.IP, nasName, dhcpServerName string) (*events.AuthRequestOption, error) {
+	if len(remoteIdBytes) == 0 && len(circuitIdBytes) == 0 {
+		return nil, nil
+	}
+	agent := new(events.AuthRequestOption)
+	parser, remoteIdFormat := o.match(nasIps, nasName, dhcpServerName)
+	var err error
+	if len(circuitIdBytes) != 0 {
+		agent.RawCircuitId = fmt.Sprintf("%x", circuitIdBytes)
+		if parser == nil {
+			parser, agent.CircuitId = redback_agent_parsers.Detect(circuitIdBytes)
+			if parser == nil {
+				err = fmt.Errorf("format of circuit-id is not detected")
+			}
+		} else {
+			agent.CircuitId, err = parser.ParseCircuitId(circuitIdBytes)
+		}
+	}
+	if parser != nil {
+		agent.Parser = parser.Name()
+	}
+	switch {
+	case remoteIdFormat != "" && remoteIdFormat != redback_agent_parsers.RemoteIdAuto:
+		agent.RemoteId, agent.RemoteIdType = redback_agent_parsers.ParseRemoteIdAs(remoteIdBytes, remoteIdFormat)
+	case parser != nil:
+		agent.RemoteId, agent.RemoteIdType = parser.ParseRemoteId(remoteIdBytes)
+	default:
+		agent.RemoteId, agent.RemoteIdType = redback_agent_parsers.ParseRemoteId(remoteIdBytes)
+	}
+	return agent, err
+}
@@ -0,0 +1,347 @@
+package radius
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/logger"
+	"github.com/meklis/all-ok-radius-server/mikrotik"
+	"github.com/meklis/all-ok-radius-server/prom"
+	"github.com/ztrue/tracerr"
+	"layeh.com/radius"
+	"layeh.com/radius/rfc2865"
+	"layeh.com/radius/rfc2869"
+)
+
+// ProxyConfig - проксирование запросов на вышестоящие RADIUS-серверы (например, старый FreeRADIUS)
+type ProxyConfig struct {
+	Timeout        time.Duration   `yaml:"timeout"`
+	Retry          time.Duration   `yaml:"retry"`
+	DisableTimeout time.Duration   `yaml:"disable_timeout"`
+	Upstreams      []ProxyUpstream `yaml:"upstreams"`
+}
+
+// ProxyUpstream - вышестоящий RADIUS-сервер и условия выбора запросов для него.
+// Все заполненные условия должны совпасть, внутри списка достаточно одного совпадения
+type ProxyUpstream struct {
+	Name          string   `yaml:"name"`
+	Secret        string   `yaml:"secret"`
+	AuthAddresses []string `yaml:"auth_addresses"`
+	AcctAddresses []string `yaml:"acct_addresses"`
+	Nas           []string `yaml:"nas"`
+	Realms        []string `yaml:"realms"`
+	DhcpServers   []string `yaml:"dhcp_servers"`
+}
+
+type proxyUpstream struct {
+	conf ProxyUpstream
+	nas  []*net.IPNet
+	auth *proxyAddresses
+	acct *proxyAddresses
+}
+
+// proxyAddresses - адреса вышестоящего сервера. Запрос уходит на живой адрес с наименьшим количеством запросов,
+// адрес, не ответивший на запрос, отключается на disable_timeout. Состояние - в метрике rad_proxy_upstream_alive_status
+type proxyAddresses struct {
+	sync.Mutex
+	upstream       string
+	addresses      []*proxyAddress
+	disableTimeout time.Duration
+	lg             *logger.Logger
+}
+
+type proxyAddress struct {
+	address       string
+	requests      int
+	disabledUntil time.Time
+}
+
+func newProxyAddresses(upstream string, addresses []string, disableTimeout time.Duration, lg *logger.Logger) *proxyAddresses {
+	p := &proxyAddresses{upstream: upstream, disableTimeout: disableTimeout, lg: lg}
+	for _, address := range addresses {
+		p.addresses = append(p.addresses, &proxyAddress{address: address})
+		prom.SetProxyUpstreamStatus(upstream, address, true)
+	}
+	return p
+}
+
+// get возвращает живой адрес и увеличивает его счетчик запросов. Адреса, у которых истек disable_timeout, включаются
+func (p *proxyAddresses) get() (string, bool) {
+	p.Lock()
+	defer p.Unlock()
+	var selected *proxyAddress
+	for _, addr := range p.addresses {
+		if !addr.disabledUntil.IsZero() {
+			if time.Now().Before(addr.disabledUntil) {
+				continue
+			}
+			addr.disabledUntil = time.Time{}
+			prom.SetProxyUpstreamStatus(p.upstream, addr.address, true)
+			p.lg.NoticeF("proxy upstream %v: address %v is alive", p.upstream, addr.address)
+		}
+		if selected == nil || addr.requests < selected.requests {
+			selected = addr
+		}
+	}
+	if selected == nil {
+		return "", false
+	}
+	selected.requests++
+	return selected.address, true
+}
+
+func (p *proxyAddresses) disable(address string) {
+	p.Lock()
+	defer p.Unlock()
+	for _, addr := range p.addresses {
+		if addr.address == address {
+			addr.disabledUntil = time.Now().Add(p.disableTimeout)
+			prom.SetProxyUpstreamStatus(p.upstream, addr.address, false)
+			p.lg.NoticeF("proxy upstream %v: address %v is dead", p.upstream, addr.address)
+		}
+	}
+}
+
+// Proxy - таблица вышестоящих RADIUS-серверов
+type Proxy struct {
+	conf      ProxyConfig
+	upstreams []*proxyUpstream
+	lg        *logger.Logger
+}
+
+// Validate проверяет настройки вышестоящих серверов, не создавая прокси
+func (c ProxyConfig) Validate() error {
+	for _, upConf := range c.Upstreams {
+		if upConf.Secret == "" {
+			return fmt.Errorf("proxy upstream %v has empty secret", upConf.Name)
+		}
+		if len(upConf.AuthAddresses) == 0 && len(upConf.AcctAddresses) == 0 {
+			return fmt.Errorf("proxy upstream %v has no addresses", upConf.Name)
+		}
+		for _, nas := range upConf.Nas {
+			if _, err := parseClientAddress(nas); err != nil {
+				return fmt.Errorf("proxy upstream %v: %v", upConf.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func NewProxy(conf ProxyConfig, lg *logger.Logger) (*Proxy, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	p := new(Proxy)
+	p.conf = conf
+	p.lg = lg
+	for _, upConf := range conf.Upstreams {
+		upConf.AuthAddresses = withDefaultPort(upConf.AuthAddresses, "1812")
+		upConf.AcctAddresses = withDefaultPort(upConf.AcctAddresses, "1813")
+		if upConf.Name == "" {
+			upConf.Name = strings.Join(append(upConf.AuthAddresses, upConf.AcctAddresses...), ",")
+		}
+		up := &proxyUpstream{conf: upConf}
+		for _, nas := range upConf.Nas {
+			network, err := parseClientAddress(nas)
+			if err != nil {
+				return nil, fmt.Errorf("proxy upstream %v: %v", upConf.Name, err)
+			}
+			up.nas = append(up.nas, network)
+		}
+		if len(upConf.AuthAddresses) != 0 {
+			up.auth = newProxyAddresses(upConf.Name, upConf.AuthAddresses, conf.DisableTimeout, lg)
+		}
+		if len(upConf.AcctAddresses) != 0 {
+			up.acct = newProxyAddresses(upConf.Name, upConf.AcctAddresses, conf.DisableTimeout, lg)
+		}
+		p.upstreams = append(p.upstreams, up)
+	}
+	return p, nil
+}
+
+func withDefaultPort(addresses []string, port string) []string {
+	result := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(strings.Trim(addr, "[]"), port)
+		}
+		result = append(result, addr)
+	}
+	return result
+}
+
+// match возвращает первый вышестоящий сервер, условия которого совпали с запросом.
+// Проксируются только Access-Request и Accounting-Request, Status-Server обрабатывается самим радиусом
+func (p *Proxy) match(r *radius.Request) *proxyUpstream {
+	if p == nil || len(p.upstreams) == 0 {
+		return nil
+	}
+	if r.Code != radius.CodeAccessRequest && r.Code != radius.CodeAccountingRequest {
+		return nil
+	}
+	nasIps := []net.IP{addrIP(r.RemoteAddr)}
+	if nasIp, err := rfc2865.NASIPAddress_Lookup(r.Packet); err == nil {
+		nasIps = append(nasIps, nasIp)
+	}
+	realm := requestRealm(r.Packet)
+	dhcpServerName := rfc2865.CalledStationID_GetString(r.Packet)
+	for _, up := range p.upstreams {
+		if r.Code == radius.CodeAccessRequest && up.auth == nil || r.Code == radius.CodeAccountingRequest && up.acct == nil {
+			continue
+		}
+		if len(up.nas) != 0 && !containsIP(up.nas, nasIps) {
+			continue
+		}
+		if len(up.conf.Realms) != 0 && !containsFold(up.conf.Realms, realm) {
+			continue
+		}
+		if len(up.conf.DhcpServers) != 0 && !containsFold(up.conf.DhcpServers, dhcpServerName) {
+			continue
+		}
+		return up
+	}
+	return nil
+}
+
+// requestRealm возвращает Mikrotik-Realm или часть User-Name после @
+func requestRealm(p *radius.Packet) string {
+	if realm := mikrotik.MikrotikRealm_GetString(p); realm != "" {
+		return realm
+	}
+	userName := rfc2865.UserName_GetString(p)
+	if pos := strings.LastIndex(userName, "@"); pos != -1 {
+		return userName[pos+1:]
+	}
+	return ""
+}
+
+func containsIP(networks []*net.IPNet, ips []net.IP) bool {
+	for _, network := range networks {
+		for _, ip := range ips {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// _handleProxy пересылает запрос на вышестоящий сервер и возвращает его ответ NAS-у.
+// Запрос переподписывается секретом вышестоящего сервера, ответ - секретом NAS-а
+func (rad *Radius) _handleProxy(w radius.ResponseWriter, r *radius.Request, up *proxyUpstream) {
+	srcs, addresses := up.auth, up.conf.AuthAddresses
+	if r.Code == radius.CodeAccountingRequest {
+		srcs, addresses = up.acct, up.conf.AcctAddresses
+	}
+	packet, proxyState, err := buildProxyPacket(r, []byte(up.conf.Secret))
+	if err != nil {
+		prom.ErrorsInc(prom.Error, "radius")
+		rad.lg.ErrorF("%v %x: error build request for upstream %v: %v", r.Code, r.Authenticator, up.conf.Name, err.Error())
+		return
+	}
+	timeout := rad.proxy.conf.Timeout
+	if timeout == 0 {
+		timeout = 3 * time.Second
+	}
+
+	//При ошибке адрес отключается и запрос отправляется на следующий живой адрес
+	var reply *radius.Packet
+	for attempt := 0; attempt < len(addresses) && reply == nil; attempt++ {
+		address, ok := srcs.get()
+		if !ok {
+			break
+		}
+		rad.lg.DebugF("%v %x: proxy to upstream %v (%v)", r.Code, r.Authenticator, up.conf.Name, address)
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		reply, err = exchangePacket(ctx, packet, address, rad.proxy.conf.Retry)
+		cancel()
+		if err != nil {
+			prom.RadProxyInc(up.conf.Name, address, "error")
+			prom.ErrorsInc(prom.Error, "radius")
+			rad.lg.ErrorF("%v %x: upstream %v (%v) returned err: %v", r.Code, r.Authenticator, up.conf.Name, address, err.Error())
+			srcs.disable(address)
+			continue
+		}
+		prom.RadProxyInc(up.conf.Name, address, reply.Code.String())
+	}
+	if reply == nil {
+		prom.RadProxyInc(up.conf.Name, "", "unavailable")
+		prom.ErrorsInc(prom.Error, "radius")
+		rad.lg.ErrorF("%v %x: not found alive addresses of upstream %v, request dropped", r.Code, r.Authenticator, up.conf.Name)
+		return
+	}
+
+	resp := r.Response(reply.Code)
+	for typ, attrs := range reply.Attributes {
+		if typ == rfc2869.MessageAuthenticator_Type {
+			continue
+		}
+		for _, attr := range attrs {
+			if typ == rfc2865.ProxyState_Type && string(attr) == string(proxyState) {
+				continue
+			}
+			resp.Add(typ, attr)
+		}
+	}
+	rad.lg.DebugF("%v %x: response from upstream %v - %v", r.Code, r.Authenticator, up.conf.Name, reply.Code)
+	if err := w.Write(resp); err != nil {
+		prom.ErrorsInc(prom.Error, "radius")
+		rad.lg.ErrorF("error write response: %v", err.Error())
+	}
+}
+
+// buildProxyPacket копирует запрос с новым аутентификатором и секретом, перешифровывает User-Password
+// и добавляет Proxy-State, по которому отличается ответ вышестоящего сервера (RFC 2865)
+func buildProxyPacket(r *radius.Request, secret []byte) (*radius.Packet, radius.Attribute, error) {
+	packet := &radius.Packet{
+		Code:       r.Code,
+		Secret:     secret,
+		Attributes: make(radius.Attributes),
+	}
+	random := make([]byte, 1+len(packet.Authenticator)+4)
+	if _, err := rand.Read(random); err != nil {
+		return nil, nil, tracerr.Wrap(err)
+	}
+	packet.Identifier = random[0]
+	copy(packet.Authenticator[:], random[1:])
+	proxyState := radius.Attribute(random[1+len(packet.Authenticator):])
+
+	for typ, attrs := range r.Attributes {
+		if typ == rfc2869.MessageAuthenticator_Type || typ == rfc2865.UserPassword_Type {
+			continue
+		}
+		for _, attr := range attrs {
+			packet.Add(typ, attr)
+		}
+	}
+	if password, err := rfc2865.UserPassword_Lookup(r.Packet); err == nil {
+		if err := rfc2865.UserPassword_Set(packet, password); err != nil {
+			return nil, nil, tracerr.Wrap(err)
+		}
+	}
+	// Без CHAP-Challenge челленджем служит аутентификатор запроса, который при проксировании меняется
+	if _, ok := r.Attributes.Lookup(rfc2865.CHAPPassword_Type); ok {
+		if _, ok := r.Attributes.Lookup(rfc2865.CHAPChallenge_Type); !ok {
+			packet.Add(rfc2865.CHAPChallenge_Type, radius.Attribute(r.Authenticator[:]))
+		}
+	}
+	packet.Add(rfc2865.ProxyState_Type, proxyState)
+	return packet, proxyState, nil
+}
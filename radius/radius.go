@@ -1,9 +1,10 @@
 package radius
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,21 +13,47 @@ import (
 	"layeh.com/radius"
 )
 
+type ctxKey int
+
+const (
+	listenerCtxKey ctxKey = iota
+	clientCtxKey
+	rawPacketCtxKey
+)
+
 type Radius struct {
-	lg         *logger.Logger
-	listenAddr string
-	secret     string
-	api        *rad_api.Api
-	classId    int64
+	lg            *logger.Logger
+	listenAddr    string
+	proto         string
+	listeners     []Listener
+	secret        string
+	clients       *Clients
+	failurePolicy FailurePolicy
+	radSec        RadSecConfig
+	duplicates    *duplicateCache
+	dynAuth       DynAuthConfig
+	dictionary    *Dictionary
+	forwardAttrs  bool
+	proxy         *Proxy
+	option82      *Option82
+	rateLimit     *rateLimiter
+	workers       *workerPool
+	servers       []server
+	serversLock   sync.Mutex
+	api           *rad_api.Api
+	classId       int64
 	sync.Mutex
 }
 
 func Init() *Radius {
 	rad := new(Radius)
 	rad.listenAddr = "0.0.0.0:1812"
+	rad.proto = "udp"
 	rad.secret = "secret"
 	rad.lg, _ = logger.New("radius", 0, os.Stdout)
 	rad.classId = time.Now().Unix()
+	rad.dictionary, _ = LoadDictionary(nil)
+	rad.workers = newWorkerPool(WorkersConfig{}, rad.lg)
 	return rad
 }
 
@@ -45,28 +72,219 @@ func (rad *Radius) SetListenAddr(listenAddr string) *Radius {
 	rad.listenAddr = listenAddr
 	return rad
 }
+func (rad *Radius) SetProto(proto string) *Radius {
+	rad.proto = proto
+	return rad
+}
+func (rad *Radius) SetListeners(listeners []Listener) *Radius {
+	rad.listeners = listeners
+	return rad
+}
 func (rad *Radius) SetSecret(secret string) *Radius {
 	rad.secret = secret
 	return rad
 }
 
+func (rad *Radius) SetClients(clients *Clients) *Radius {
+	rad.clients = clients
+	return rad
+}
+
+func (rad *Radius) SetFailurePolicy(policy FailurePolicy) *Radius {
+	rad.failurePolicy = policy
+	return rad
+}
+
+func (rad *Radius) SetRadSec(conf RadSecConfig) *Radius {
+	rad.radSec = conf
+	return rad
+}
+
+// SetDuplicateCacheTTL включает кеширование ответов для ретрансмитов. 0 - выключено
+func (rad *Radius) SetDuplicateCacheTTL(ttl time.Duration) *Radius {
+	if ttl <= 0 {
+		rad.duplicates = nil
+		return rad
+	}
+	rad.duplicates = newDuplicateCache(ttl)
+	return rad
+}
+
+func (rad *Radius) SetDynAuth(conf DynAuthConfig) *Radius {
+	rad.dynAuth = conf
+	return rad
+}
+
+func (rad *Radius) SetDictionary(dictionary *Dictionary) *Radius {
+	rad.dictionary = dictionary
+	return rad
+}
+
+// SetForwardAttributes включает передачу всех атрибутов запроса на API в поле attributes
+func (rad *Radius) SetForwardAttributes(enabled bool) *Radius {
+	rad.forwardAttrs = enabled
+	return rad
+}
+
+// SetProxy задает вышестоящие RADIUS-серверы, на которые пересылаются выбранные запросы
+func (rad *Radius) SetProxy(proxy *Proxy) *Radius {
+	rad.proxy = proxy
+	return rad
+}
+
+// SetOption82 задает выбор парсера option82 по NAS и dhcp-серверу
+func (rad *Radius) SetOption82(option82 *Option82) *Radius {
+	rad.option82 = option82
+	return rad
+}
+
+// SetRateLimit задает ограничение частоты Access-Request по NAS и MAC
+func (rad *Radius) SetRateLimit(conf RateLimitConfig) *Radius {
+	rad.rateLimit = newRateLimiter(conf)
+	return rad
+}
+
+// SetWorkers ограничивает количество одновременно обрабатываемых запросов
+func (rad *Radius) SetWorkers(conf WorkersConfig) *Radius {
+	rad.workers = newWorkerPool(conf, rad.lg)
+	return rad
+}
+
 func (rad *Radius) SetAPI(apiR *rad_api.Api) *Radius {
 	rad.api = apiR
 	return rad
 }
 
 func (rad *Radius) ListenAndServe() error {
-	server := radius.PacketServer{
-		Addr:         rad.listenAddr,
-		Network:      "udp",
-		SecretSource: radius.StaticSecretSource([]byte(rad.secret)),
-		Handler:      radius.HandlerFunc(rad.handler),
+	var secretSource radius.SecretSource = radius.StaticSecretSource([]byte(rad.secret))
+	if rad.clients != nil {
+		secretSource = rad.clients
+	}
+	listeners := rad.listeners
+	if len(listeners) == 0 {
+		listeners = []Listener{{ListenAddr: rad.listenAddr, Proto: rad.proto, Role: RoleBoth}}
+	}
+
+	errs := make(chan error, 2*len(listeners)+1)
+	for _, l := range listeners {
+		if err := l.Validate(); err != nil {
+			return err
+		}
+		if l.Role == "" {
+			l.Role = RoleBoth
+		}
+		protocols, _ := parseProto(l.Proto)
+		for _, proto := range protocols {
+			listener := l
+			if listener.Name == "" {
+				listener.Name = fmt.Sprintf("%v/%v", l.ListenAddr, proto)
+			}
+			var srv server
+			switch proto {
+			case "udp":
+				srv = &packetServer{
+					addr:         listener.ListenAddr,
+					network:      "udp",
+					secretSource: secretSource,
+					handler:      rad.listenerHandler(listener),
+					pool:         rad.workers,
+					lg:           rad.lg,
+				}
+			case "tcp":
+				srv = &streamServer{
+					addr:         listener.ListenAddr,
+					network:      "tcp",
+					secretSource: secretSource,
+					handler:      rad.listenerHandler(listener),
+					pool:         rad.workers,
+					lg:           rad.lg,
+				}
+			}
+			if err := srv.listen(); err != nil {
+				return err
+			}
+			rad.addServer(srv)
+			go func() {
+				errs <- srv.Serve()
+			}()
+			rad.lg.InfoF("Starting radius server on %v/%v, listener=%v role=%v", listener.ListenAddr, proto, listener.Name, listener.Role)
+		}
+	}
+	if rad.radSec.Enabled {
+		srv, err := rad.listenRadSec()
+		if err != nil {
+			return err
+		}
+		rad.addServer(srv)
+		go func() {
+			errs <- srv.Serve()
+		}()
 	}
+	return <-errs
+}
 
-	rad.lg.InfoF("Starting radius server on %v", rad.listenAddr)
-	if err := server.ListenAndServe(); err != nil {
-		log.Fatal(err)
-		return err
+// server - листенер радиуса (UDP, TCP или TLS)
+type server interface {
+	listen() error
+	Serve() error
+	stop()
+	close()
+}
+
+func (rad *Radius) addServer(srv server) {
+	rad.serversLock.Lock()
+	defer rad.serversLock.Unlock()
+	rad.servers = append(rad.servers, srv)
+}
+
+// Shutdown прекращает прием новых запросов и ждет, пока будут обработаны уже принятые запросы.
+// Если ctx завершится раньше - возвращает ошибку с количеством необработанных запросов
+func (rad *Radius) Shutdown(ctx context.Context) error {
+	rad.serversLock.Lock()
+	servers := rad.servers
+	rad.servers = nil
+	rad.serversLock.Unlock()
+
+	rad.lg.NoticeF("radius is stopping, new requests will not be accepted")
+	for _, srv := range servers {
+		srv.stop()
+	}
+	err := rad.workers.wait(ctx)
+	for _, srv := range servers {
+		srv.close()
+	}
+	return err
+}
+
+// requestClient возвращает клиента, от которого пришел запрос
+func (rad *Radius) requestClient(r *radius.Request) (*Client, bool) {
+	if client, ok := r.Context().Value(clientCtxKey).(*Client); ok {
+		return client, true
+	}
+	if rad.clients == nil {
+		return nil, false
+	}
+	return rad.clients.Find(addrIP(r.RemoteAddr))
+}
+
+func rawPacketFromContext(ctx context.Context) ([]byte, bool) {
+	raw, ok := ctx.Value(rawPacketCtxKey).([]byte)
+	return raw, ok
+}
+
+func parseProto(proto string) ([]string, error) {
+	if strings.TrimSpace(proto) == "" {
+		return []string{"udp"}, nil
+	}
+	protocols := make([]string, 0)
+	for _, p := range strings.Split(proto, ",") {
+		p = strings.ToLower(strings.TrimSpace(p))
+		switch p {
+		case "udp", "tcp":
+			protocols = append(protocols, p)
+		default:
+			return nil, fmt.Errorf("unsupported radius proto '%v', must be udp, tcp or udp,tcp", p)
+		}
 	}
-	return nil
+	return protocols, nil
 }
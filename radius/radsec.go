@@ -0,0 +1,124 @@
+package radius
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/prom"
+	"github.com/ztrue/tracerr"
+)
+
+// RFC 6614, 2.3 - для RadSec используется фиксированный секрет "radsec"
+const radSecSecret = "radsec"
+
+const radSecHandshakeTimeout = 10 * time.Second
+
+type RadSecConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	ListenAddr   string `yaml:"listen_addr"`
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	ClientCaFile string `yaml:"client_ca_file"`
+}
+
+// Validate проверяет, что сертификаты RadSec читаются, если RadSec включен
+func (c RadSecConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	_, err := c.tlsConfig()
+	return err
+}
+
+func (c RadSecConfig) tlsConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, tracerr.Wrap(err)
+	}
+	caBytes, err := ioutil.ReadFile(c.ClientCaFile)
+	if err != nil {
+		return nil, tracerr.Wrap(err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caBytes) {
+		return nil, tracerr.New(fmt.Sprintf("not found certificates in client CA file %v", c.ClientCaFile))
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func (rad *Radius) listenRadSec() (*streamServer, error) {
+	tlsConf, err := rad.radSec.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	listenAddr := rad.radSec.ListenAddr
+	if listenAddr == "" {
+		listenAddr = "0.0.0.0:2083"
+	}
+	listener, err := tls.Listen("tcp", listenAddr, tlsConf)
+	if err != nil {
+		return nil, err
+	}
+	server := &streamServer{
+		addr:     listenAddr,
+		network:  "tls",
+		handler:  rad.listenerHandler(Listener{Name: "radsec", ListenAddr: listenAddr, Proto: "tls", Role: RoleBoth}),
+		identify: rad.identifyRadSecClient,
+		pool:     rad.workers,
+		lg:       rad.lg,
+		listener: listener,
+	}
+	rad.lg.InfoF("Starting radius server on %v/tls", listenAddr)
+	return server, nil
+}
+
+// identifyRadSecClient сопоставляет сертификат NAS-а с записью из таблицы клиентов по cert_identity
+func (rad *Radius) identifyRadSecClient(conn net.Conn) (*Client, error) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, tracerr.New("connection is not TLS")
+	}
+	tlsConn.SetDeadline(time.Now().Add(radSecHandshakeTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		prom.ErrorsInc(prom.Warning, "radius")
+		return nil, tracerr.Wrap(err)
+	}
+	tlsConn.SetDeadline(time.Time{})
+
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, tracerr.New("client certificate not presented")
+	}
+	identities := certIdentities(certs[0])
+	if rad.clients != nil {
+		if client, ok := rad.clients.FindByIdentity(identities); ok {
+			return client, nil
+		}
+	}
+	prom.RadUnknownClientInc("certificate")
+	return nil, tracerr.New(fmt.Sprintf("not found client for certificate identities %v", identities))
+}
+
+func certIdentities(cert *x509.Certificate) []string {
+	identities := make([]string, 0)
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+	identities = append(identities, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		identities = append(identities, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	return identities
+}
@@ -0,0 +1,162 @@
+package radius
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/prom"
+	"github.com/meklis/all-ok-radius-server/radius/events"
+	"github.com/meklis/go-cache"
+	"layeh.com/radius"
+)
+
+const (
+	RateLimitActionDrop   = "drop"
+	RateLimitActionReject = "reject"
+	RateLimitActionCache  = "cache"
+)
+
+// RateLimitConfig - ограничение частоты Access-Request от одного NAS и для одного MAC (защита от DHCP-штормов)
+type RateLimitConfig struct {
+	Action       string          `yaml:"action"`
+	ReplyMessage string          `yaml:"reply_message"`
+	Nas          RateLimitBucket `yaml:"nas"`
+	Mac          RateLimitBucket `yaml:"mac"`
+}
+
+// RateLimitBucket - параметры token bucket: rate запросов в секунду, burst - размер корзины. rate: 0 - без ограничения
+type RateLimitBucket struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+func (c RateLimitConfig) Validate() error {
+	switch c.Action {
+	case "", RateLimitActionDrop, RateLimitActionReject, RateLimitActionCache:
+	default:
+		return fmt.Errorf("unknown rate limit action '%v', must be one of: drop, reject, cache", c.Action)
+	}
+	if c.Nas.Rate < 0 || c.Mac.Rate < 0 {
+		return fmt.Errorf("rate limit rate must be positive")
+	}
+	return nil
+}
+
+func (c RateLimitBucket) size() float64 {
+	if c.Burst < 1 {
+		return 1
+	}
+	return float64(c.Burst)
+}
+
+type tokenBucket struct {
+	sync.Mutex
+	tokens  float64
+	last    time.Time
+	limited bool
+}
+
+// take забирает токен. Возвращает false, если корзина пуста, и признак того, что ограничение только что включилось
+func (b *tokenBucket) take(conf RateLimitBucket, now time.Time) (allowed bool, started bool) {
+	b.Lock()
+	defer b.Unlock()
+	burst := conf.size()
+	b.tokens += now.Sub(b.last).Seconds() * conf.Rate
+	if b.tokens > burst {
+		b.tokens = burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		started = !b.limited
+		b.limited = true
+		return false, started
+	}
+	b.tokens--
+	b.limited = false
+	return true, false
+}
+
+type rateLimiter struct {
+	conf    RateLimitConfig
+	buckets *cache.Cache
+}
+
+func newRateLimiter(conf RateLimitConfig) *rateLimiter {
+	if conf.Nas.Rate == 0 && conf.Mac.Rate == 0 {
+		return nil
+	}
+	return &rateLimiter{
+		conf:    conf,
+		buckets: cache.New(time.Minute, time.Minute),
+	}
+}
+
+func (l *rateLimiter) take(key string, conf RateLimitBucket) (bool, bool) {
+	if conf.Rate == 0 {
+		return true, false
+	}
+	now := time.Now()
+	bucket := &tokenBucket{tokens: conf.size(), last: now}
+	if err := l.buckets.Add(key, bucket, cache.DefaultExpiration); err != nil {
+		if exist, ok := l.buckets.Get(key); ok {
+			bucket = exist.(*tokenBucket)
+		}
+	}
+	//Продлевает время жизни корзины, пока от NAS/MAC идут запросы
+	l.buckets.SetDefault(key, bucket)
+	return bucket.take(conf, now)
+}
+
+// check возвращает название превышенного ограничения (mac или nas) или пустую строку.
+// Сначала проверяется MAC, чтобы шторм от одного абонента не расходовал лимит всего NAS-а
+func (l *rateLimiter) check(nas, mac string) (limit string, started bool) {
+	if l == nil {
+		return "", false
+	}
+	if allowed, started := l.take("mac|"+nas+"|"+mac, l.conf.Mac); !allowed {
+		return "mac", started
+	}
+	if allowed, started := l.take("nas|"+nas, l.conf.Nas); !allowed {
+		return "nas", started
+	}
+	return "", false
+}
+
+// _handleRateLimited отвечает на запрос, превысивший ограничение, в соответствии с radius.rate_limit.action.
+// Запросы к API и PostAuth не отправляются
+func (rad *Radius) _handleRateLimited(w radius.ResponseWriter, r *radius.Request, req events.AuthRequest, nas, limit string, started bool) {
+	action := rad.rateLimit.conf.Action
+	if action == "" {
+		action = RateLimitActionDrop
+	}
+	prom.RadRateLimitedInc(nas, req.DeviceMac, limit, action)
+	if started {
+		rad.lg.WarningF("%v rate limit exceeded for NAS %v, MAC %v, requests will be processed with action=%v", limit, nas, req.DeviceMac, action)
+	}
+	rad.lg.DebugF("%v %x: %v rate limit exceeded, action=%v", r.Code, r.Authenticator, limit, action)
+
+	classId := req.Class
+	var err error
+	switch action {
+	case RateLimitActionReject:
+		prom.RadRejectsInc(req.NasIp)
+		err = rad._respondAuthReject(events.AuthResponse{Reject: true, ReplyMessage: rad.rateLimit.conf.ReplyMessage, Class: classId}, w, r)
+	case RateLimitActionCache:
+		resp, ok := rad.api.GetCached(&req)
+		if !ok {
+			rad.lg.DebugF("%v %x: response not found in cache, request dropped", r.Code, r.Authenticator)
+			return
+		}
+		resp.Class = classId
+		if resp.Reject {
+			err = rad._respondAuthReject(*resp, w, r)
+		} else {
+			err = rad._respondAuthAccept(*resp, w, r)
+		}
+	}
+	if err != nil {
+		prom.ErrorsInc(prom.Error, "radius")
+		rad.lg.ErrorF("error write response: %v", err.Error())
+	}
+}
@@ -0,0 +1,139 @@
+package radius
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/logger"
+	"layeh.com/radius"
+)
+
+type packetResponseWriter struct {
+	conn net.PacketConn
+	addr net.Addr
+}
+
+func (w *packetResponseWriter) Write(packet *radius.Packet) error {
+	encoded, err := encodePacket(packet)
+	if err != nil {
+		return err
+	}
+	if _, err := w.conn.WriteTo(encoded, w.addr); err != nil {
+		return err
+	}
+	return nil
+}
+
+type requestKey struct {
+	addr       string
+	identifier byte
+}
+
+// packetServer принимает RADIUS-запросы поверх UDP.
+// В отличие от radius.PacketServer передает в обработчик исходные байты пакета (для проверки Message-Authenticator)
+// и кодирует ответы с Message-Authenticator первым атрибутом
+type packetServer struct {
+	addr         string
+	network      string
+	secretSource radius.SecretSource
+	handler      radius.Handler
+	pool         *workerPool
+	lg           *logger.Logger
+	conn         net.PacketConn
+	stopped      int32
+
+	requestsLock sync.Mutex
+	requests     map[requestKey]struct{}
+}
+
+func (s *packetServer) listen() error {
+	conn, err := net.ListenPacket(s.network, s.addr)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// stop прекращает чтение новых пакетов. Сокет остается открытым для ответов на уже принятые запросы
+func (s *packetServer) stop() {
+	atomic.StoreInt32(&s.stopped, 1)
+	s.conn.SetReadDeadline(time.Now())
+}
+
+func (s *packetServer) close() {
+	s.conn.Close()
+}
+
+func (s *packetServer) Serve() error {
+	if s.handler == nil || s.secretSource == nil {
+		return errors.New("radius: nil handler or secret source")
+	}
+	s.requests = make(map[requestKey]struct{})
+
+	conn := s.conn
+	buff := make([]byte, radius.MaxPacketLength)
+	for {
+		n, remoteAddr, err := conn.ReadFrom(buff)
+		if err != nil {
+			if atomic.LoadInt32(&s.stopped) == 1 {
+				return nil
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return err
+		}
+		raw := append([]byte(nil), buff[:n]...)
+		s.pool.run(func() {
+			s.servePacket(conn, raw, remoteAddr)
+		}, nil)
+	}
+}
+
+func (s *packetServer) servePacket(conn net.PacketConn, raw []byte, remoteAddr net.Addr) {
+	ctx := context.Background()
+	secret, err := s.secretSource.RADIUSSecret(ctx, remoteAddr)
+	if err != nil || len(secret) == 0 {
+		return
+	}
+	packet, err := parseRequest(raw, secret)
+	if err != nil {
+		s.lg.DebugF("packet from %v dropped: %v", remoteAddr.String(), err)
+		return
+	}
+
+	// Повторы запроса, который еще обрабатывается, отбрасываются
+	key := requestKey{addr: remoteAddr.String(), identifier: packet.Identifier}
+	s.requestsLock.Lock()
+	if _, ok := s.requests[key]; ok {
+		s.requestsLock.Unlock()
+		return
+	}
+	s.requests[key] = struct{}{}
+	s.requestsLock.Unlock()
+	defer func() {
+		s.requestsLock.Lock()
+		delete(s.requests, key)
+		s.requestsLock.Unlock()
+	}()
+
+	request := &radius.Request{
+		LocalAddr:  conn.LocalAddr(),
+		RemoteAddr: remoteAddr,
+		Packet:     packet,
+	}
+	writer := &packetResponseWriter{conn: conn, addr: remoteAddr}
+	s.handler.ServeRADIUS(writer, request.WithContext(context.WithValue(ctx, rawPacketCtxKey, raw)))
+}
+
+func parseRequest(raw []byte, secret []byte) (*radius.Packet, error) {
+	if !radius.IsAuthenticRequest(raw, secret) {
+		return nil, errors.New("non authentic request")
+	}
+	return radius.Parse(raw, secret)
+}
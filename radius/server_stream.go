@@ -0,0 +1,184 @@
+package radius
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/logger"
+	"github.com/meklis/all-ok-radius-server/prom"
+	"layeh.com/radius"
+)
+
+const streamIdleTimeout = 2 * time.Minute
+
+type streamResponseWriter struct {
+	sync.Mutex
+	conn net.Conn
+}
+
+func (w *streamResponseWriter) Write(packet *radius.Packet) error {
+	encoded, err := encodePacket(packet)
+	if err != nil {
+		return err
+	}
+	w.Lock()
+	defer w.Unlock()
+	if _, err := w.conn.Write(encoded); err != nil {
+		return err
+	}
+	return nil
+}
+
+// streamServer принимает RADIUS-запросы поверх TCP (RFC 6613) или TLS (RFC 6614).
+// Если задан identify - клиент определяется по соединению (сертификату), а не по secretSource
+type streamServer struct {
+	addr         string
+	network      string
+	secretSource radius.SecretSource
+	identify     func(conn net.Conn) (*Client, error)
+	handler      radius.Handler
+	pool         *workerPool
+	lg           *logger.Logger
+	listener     net.Listener
+	stopped      int32
+
+	connsLock sync.Mutex
+	conns     map[net.Conn]struct{}
+}
+
+func (s *streamServer) listen() error {
+	listener, err := net.Listen(s.network, s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	return nil
+}
+
+// stop закрывает листенер и прекращает чтение из открытых соединений.
+// Соединение закрывается после отправки ответов на уже принятые запросы
+func (s *streamServer) stop() {
+	atomic.StoreInt32(&s.stopped, 1)
+	s.listener.Close()
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+	for conn := range s.conns {
+		conn.SetReadDeadline(time.Now())
+	}
+}
+
+func (s *streamServer) close() {
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *streamServer) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if atomic.LoadInt32(&s.stopped) == 1 {
+				return nil
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *streamServer) trackConn(conn net.Conn, add bool) {
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]struct{})
+	}
+	if add {
+		s.conns[conn] = struct{}{}
+	} else {
+		delete(s.conns, conn)
+	}
+}
+
+func (s *streamServer) serveConn(conn net.Conn) {
+	s.trackConn(conn, true)
+	// Соединение закрывается только после ответов на все принятые по нему запросы
+	var pending sync.WaitGroup
+	defer func() {
+		pending.Wait()
+		conn.Close()
+		s.trackConn(conn, false)
+	}()
+	ctx := context.Background()
+	var secret []byte
+	if s.identify != nil {
+		client, err := s.identify(conn)
+		if err != nil {
+			s.lg.WarningF("%v connection from %v rejected: %v", s.network, conn.RemoteAddr().String(), err)
+			return
+		}
+		s.lg.DebugF("new %v connection from %v identified as client %v", s.network, conn.RemoteAddr().String(), client.Name)
+		secret = []byte(radSecSecret)
+		ctx = context.WithValue(ctx, clientCtxKey, client)
+	} else {
+		var err error
+		secret, err = s.secretSource.RADIUSSecret(ctx, conn.RemoteAddr())
+		if err != nil || len(secret) == 0 {
+			return
+		}
+		s.lg.DebugF("new %v connection from %v", s.network, conn.RemoteAddr().String())
+	}
+
+	writer := &streamResponseWriter{conn: conn}
+	header := make([]byte, 4)
+	for {
+		conn.SetReadDeadline(time.Now().Add(streamIdleTimeout))
+		if atomic.LoadInt32(&s.stopped) == 1 {
+			return
+		}
+		if _, err := io.ReadFull(conn, header); err != nil {
+			if err != io.EOF {
+				s.lg.DebugF("%v connection from %v closed: %v", s.network, conn.RemoteAddr().String(), err)
+			}
+			return
+		}
+		length := int(binary.BigEndian.Uint16(header[2:4]))
+		if length < 20 || length > radius.MaxPacketLength {
+			prom.ErrorsInc(prom.Warning, "radius")
+			s.lg.WarningF("invalid packet length %v from %v, closing connection", length, conn.RemoteAddr().String())
+			return
+		}
+		buff := make([]byte, length)
+		copy(buff, header)
+		if _, err := io.ReadFull(conn, buff[4:]); err != nil {
+			return
+		}
+		// RFC 6613, 2.6.4 - при получении невалидного пакета соединение закрывается
+		packet, err := parseRequest(buff, secret)
+		if err != nil {
+			prom.ErrorsInc(prom.Warning, "radius")
+			s.lg.WarningF("invalid packet from %v, closing connection: %v", conn.RemoteAddr().String(), err)
+			return
+		}
+		request := &radius.Request{
+			LocalAddr:  conn.LocalAddr(),
+			RemoteAddr: conn.RemoteAddr(),
+			Packet:     packet,
+		}
+		request = request.WithContext(context.WithValue(ctx, rawPacketCtxKey, buff))
+		pending.Add(1)
+		s.pool.run(func() {
+			defer pending.Done()
+			s.handler.ServeRADIUS(writer, request)
+		}, pending.Done)
+	}
+}
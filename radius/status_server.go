@@ -0,0 +1,45 @@
+package radius
+
+import (
+	"net"
+
+	"github.com/meklis/all-ok-radius-server/prom"
+	"layeh.com/radius"
+)
+
+// _handleStatusServer отвечает на Status-Server (RFC 5997).
+// Ответ отправляется только если доступен хотя бы один источник API,
+// иначе запрос игнорируется - NAS сможет переключиться на резервный радиус
+func (rad *Radius) _handleStatusServer(w radius.ResponseWriter, r *radius.Request) {
+	host := addrIP(r.RemoteAddr).String()
+	alive := rad.api.IsAlive()
+	prom.RadStatusServerInc(host, alive)
+	if !alive {
+		rad.lg.WarningF("%v %x: no alive api sources, status-server from %v ignored", r.Code, r.Authenticator, host)
+		return
+	}
+
+	// RFC 5997, 3 - на порту аккаунтинга отвечаем Accounting-Response
+	code := radius.CodeAccessAccept
+	if l, ok := listenerFromContext(r.Context()); ok && l.Role == RoleAcct {
+		code = radius.CodeAccountingResponse
+	} else if (!ok || l.Role == RoleBoth) && isAccountingAddr(r.LocalAddr) {
+		code = radius.CodeAccountingResponse
+	}
+	response := r.Response(code)
+	rad.lg.DebugF("%v %x: api is alive, respond %v", r.Code, r.Authenticator, code)
+	if err := w.Write(response); err != nil {
+		prom.ErrorsInc(prom.Error, "radius")
+		rad.lg.ErrorF("error write status-server response: %v", err.Error())
+	}
+}
+
+func isAccountingAddr(addr net.Addr) bool {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.Port == 1813 || a.Port == 1646
+	case *net.TCPAddr:
+		return a.Port == 1813 || a.Port == 1646
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package radius
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/meklis/all-ok-radius-server/logger"
+	"github.com/meklis/all-ok-radius-server/prom"
+)
+
+// WorkersConfig - ограничение количества одновременно обрабатываемых запросов.
+// max_in_flight: 0 - без ограничения, каждый запрос обрабатывается в отдельной горутине
+type WorkersConfig struct {
+	MaxInFlight  int           `yaml:"max_in_flight"`
+	QueueSize    int           `yaml:"queue_size"`
+	QueueTimeout time.Duration `yaml:"queue_timeout"`
+}
+
+type workerJob struct {
+	run      func()
+	drop     func()
+	deadline time.Time
+}
+
+// workerPool - фиксированное количество обработчиков и очередь запросов перед ними.
+// Если очередь заполнена или запрос простоял в ней дольше queue_timeout - запрос отбрасывается без ответа,
+// NAS повторит его или переключится на другой радиус
+type workerPool struct {
+	conf     WorkersConfig
+	jobs     chan workerJob
+	inFlight int64
+	lg       *logger.Logger
+
+	//Принятые и еще не обработанные запросы (в очереди и в обработке), ожидаются при завершении работы
+	pending      sync.WaitGroup
+	pendingCount int64
+}
+
+func newWorkerPool(conf WorkersConfig, lg *logger.Logger) *workerPool {
+	p := &workerPool{
+		conf: conf,
+		lg:   lg,
+	}
+	if conf.MaxInFlight <= 0 {
+		return p
+	}
+	if p.conf.QueueSize < 0 {
+		p.conf.QueueSize = 0
+	}
+	p.jobs = make(chan workerJob, p.conf.QueueSize)
+	for i := 0; i < conf.MaxInFlight; i++ {
+		go p.worker()
+	}
+	go func() {
+		for {
+			time.Sleep(time.Second)
+			prom.SetWorkerQueueSize(len(p.jobs))
+			prom.SetWorkerInFlight(int(atomic.LoadInt64(&p.inFlight)))
+		}
+	}()
+	return p
+}
+
+func (p *workerPool) worker() {
+	for job := range p.jobs {
+		if !job.deadline.IsZero() && time.Now().After(job.deadline) {
+			prom.RadWorkerShedInc("expired")
+			p.lg.DebugF("request expired in queue, dropped")
+			if job.drop != nil {
+				job.drop()
+			}
+			p.done()
+			continue
+		}
+		atomic.AddInt64(&p.inFlight, 1)
+		job.run()
+		atomic.AddInt64(&p.inFlight, -1)
+		p.done()
+	}
+}
+
+func (p *workerPool) done() {
+	atomic.AddInt64(&p.pendingCount, -1)
+	p.pending.Done()
+}
+
+// run передает запрос в очередь обработчиков. Без ограничения запрос обрабатывается в новой горутине.
+// drop (если задан) вызывается вместо fn, если запрос отброшен
+func (p *workerPool) run(fn func(), drop func()) {
+	p.pending.Add(1)
+	atomic.AddInt64(&p.pendingCount, 1)
+	if p.jobs == nil {
+		go func() {
+			defer p.done()
+			fn()
+		}()
+		return
+	}
+	job := workerJob{run: fn, drop: drop}
+	if p.conf.QueueTimeout > 0 {
+		job.deadline = time.Now().Add(p.conf.QueueTimeout)
+	}
+	select {
+	case p.jobs <- job:
+	default:
+		if drop != nil {
+			drop()
+		}
+		p.done()
+		prom.RadWorkerShedInc("queue_full")
+		p.lg.WarningF("workers queue is full, request dropped. Try to increase radius.workers.max_in_flight or queue_size")
+	}
+}
+
+// wait ждет завершения обработки всех принятых запросов
+func (p *workerPool) wait(ctx context.Context) error {
+	finished := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%v requests are not finished", atomic.LoadInt64(&p.pendingCount))
+	}
+}
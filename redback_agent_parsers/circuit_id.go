@@ -0,0 +1,43 @@
+package redback_agent_parsers
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// CircuitId - разобранный Circuit-Id (option82), общий для всех производителей.
+// Interface и Hostname заполняются, если производитель передает их в Circuit-Id,
+// Fields - остальные поля парсеров из конфигурации.
+// Olt, Slot, PonPort и OnuId заполняются для абонентов за OLT (GPON/EPON)
+type CircuitId struct {
+	VlanId    int               `json:"vlan_id"`
+	Module    int               `json:"module"`
+	Port      int               `json:"port"`
+	Interface string            `json:"interface,omitempty"`
+	Hostname  string            `json:"hostname,omitempty"`
+	Olt       string            `json:"olt,omitempty"`
+	Slot      *int              `json:"slot,omitempty"`
+	PonPort   *int              `json:"pon_port,omitempty"`
+	OnuId     *int              `json:"onu_id,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+// ParseCircuitId разбирает Circuit-Id коммутаторов D-Link:
+// тип circuit-id (0), длина (4), VLAN (2 байта), модуль (1 байт), порт (1 байт)
+func ParseCircuitId(circuitIdBytes []byte) (*CircuitId, error) {
+	if len(circuitIdBytes) != 6 {
+		return nil, fmt.Errorf("unexpected circuit-id length %v, expected 6", len(circuitIdBytes))
+	}
+	if circuitIdBytes[0] != 0 || circuitIdBytes[1] != 4 {
+		return nil, fmt.Errorf("unexpected circuit-id type %v with length %v", circuitIdBytes[0], circuitIdBytes[1])
+	}
+	circuitId := &CircuitId{
+		VlanId: int(binary.BigEndian.Uint16(circuitIdBytes[2:4])),
+		Module: int(circuitIdBytes[4]),
+		Port:   int(circuitIdBytes[5]),
+	}
+	if circuitId.VlanId > 4095 {
+		return nil, fmt.Errorf("circuit-id contains wrong vlan %v", circuitId.VlanId)
+	}
+	return circuitId, nil
+}
@@ -0,0 +1,47 @@
+package redback_agent_parsers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCircuitIdDlink(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		want    *CircuitId
+		wantErr bool
+	}{
+		{"vlan 100 module 1 port 5", []byte{0x00, 0x04, 0x00, 0x64, 0x01, 0x05}, &CircuitId{VlanId: 100, Module: 1, Port: 5}, false},
+		{"vlan 4095", []byte{0x00, 0x04, 0x0f, 0xff, 0x00, 0x30}, &CircuitId{VlanId: 4095, Module: 0, Port: 48}, false},
+		{"vlan above 4095", []byte{0x00, 0x04, 0x10, 0x00, 0x01, 0x01}, nil, true},
+		{"wrong circuit-id type", []byte{0x01, 0x04, 0x00, 0x64, 0x01, 0x05}, nil, true},
+		{"wrong sub-option length", []byte{0x00, 0x05, 0x00, 0x64, 0x01, 0x05}, nil, true},
+		{"too short", []byte{0x00, 0x04, 0x00, 0x64, 0x01}, nil, true},
+		{"too long", []byte{0x00, 0x04, 0x00, 0x64, 0x01, 0x05, 0x00}, nil, true},
+		{"header only", []byte{0x00, 0x04}, nil, true},
+		{"empty", []byte{}, nil, true},
+		{"nil", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCircuitId(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCircuitId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseCircuitId() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDlink(t *testing.T) {
+	parser, circuitId := Detect([]byte{0x00, 0x04, 0x00, 0x64, 0x01, 0x05})
+	if parser == nil || parser.Name() != "dlink" {
+		t.Fatalf("Detect() parser = %v, expected dlink", parser)
+	}
+	if circuitId.VlanId != 100 || circuitId.Module != 1 || circuitId.Port != 5 {
+		t.Fatalf("Detect() circuit-id = %+v", circuitId)
+	}
+}
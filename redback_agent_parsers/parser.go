@@ -0,0 +1,60 @@
+package redback_agent_parsers
+
+import (
+	"sync"
+)
+
+// Parser - разбор option82 оборудования конкретного производителя.
+// Результат разбора Circuit-Id приводится к общему виду CircuitId,
+// ParseRemoteId возвращает Remote-Id и его формат (mac, ascii, hex)
+type Parser interface {
+	Name() string
+	ParseCircuitId(circuitIdBytes []byte) (*CircuitId, error)
+	ParseRemoteId(remoteIdBytes []byte) (string, string)
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = make(map[string]Parser)
+	//Порядок регистрации, в нем парсеры перебираются при автоопределении
+	registryOrder []string
+)
+
+// Register добавляет парсер в реестр. Парсер с тем же именем заменяется
+func Register(parser Parser) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, exist := registry[parser.Name()]; !exist {
+		registryOrder = append(registryOrder, parser.Name())
+	}
+	registry[parser.Name()] = parser
+}
+
+// Get возвращает парсер по имени
+func Get(name string) (Parser, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	parser, ok := registry[name]
+	return parser, ok
+}
+
+// Parsers возвращает зарегистрированные парсеры в порядке регистрации
+func Parsers() []Parser {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	parsers := make([]Parser, 0, len(registryOrder))
+	for _, name := range registryOrder {
+		parsers = append(parsers, registry[name])
+	}
+	return parsers
+}
+
+// Detect перебирает парсеры и возвращает первый, который смог разобрать Circuit-Id
+func Detect(circuitIdBytes []byte) (Parser, *CircuitId) {
+	for _, parser := range Parsers() {
+		if circuitId, err := parser.ParseCircuitId(circuitIdBytes); err == nil {
+			return parser, circuitId
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,92 @@
+package redback_agent_parsers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ponParser - текстовый Circuit-Id OLT в стиле TR-101:
+// "<olt> <xpon|gpon|epon|xgpon|xgspon> [<frame>/]<slot>/<pon_port>:<onu_id>[.<vlan>|:<vlan>]", например "OLT1 xpon 0/1/3:12".
+// Модуль и порт заполняются слотом и PON-портом
+type ponParser struct{}
+
+var ponRe = regexp.MustCompile(`^(?P<olt>\S+) (?P<technology>(?i:xpon|gpon|epon|xgpon|xgspon)) (?:(?P<frame>\d+)/)?(?P<slot>\d+)/(?P<pon_port>\d+):(?P<onu_id>\d+)(?:[.:](?P<vlan>\d+))?$`)
+
+func (ponParser) Name() string {
+	return "pon"
+}
+
+func (ponParser) ParseCircuitId(circuitIdBytes []byte) (*CircuitId, error) {
+	match := ponRe.FindStringSubmatch(string(circuitIdBytes))
+	if match == nil {
+		return nil, fmt.Errorf("circuit-id does not match pon format")
+	}
+	groups := make(map[string]string)
+	for i, group := range ponRe.SubexpNames() {
+		groups[group] = match[i]
+	}
+	numbers := make(map[string]int)
+	for _, group := range []string{"slot", "pon_port", "onu_id", "vlan"} {
+		if groups[group] == "" {
+			continue
+		}
+		number, err := strconv.Atoi(groups[group])
+		if err != nil {
+			return nil, fmt.Errorf("circuit-id contains wrong %v %v", group, groups[group])
+		}
+		numbers[group] = number
+	}
+	if numbers["vlan"] > 4095 {
+		return nil, fmt.Errorf("circuit-id contains wrong vlan %v", numbers["vlan"])
+	}
+	ponInterface := groups["slot"] + "/" + groups["pon_port"]
+	if groups["frame"] != "" {
+		ponInterface = groups["frame"] + "/" + ponInterface
+	}
+	return newPonCircuitId(groups["olt"], groups["technology"]+" "+ponInterface, numbers["slot"], numbers["pon_port"], numbers["onu_id"], numbers["vlan"]), nil
+}
+
+func (ponParser) ParseRemoteId(remoteIdBytes []byte) (string, string) {
+	return ParseRemoteId(remoteIdBytes)
+}
+
+// ponBinaryParser - двоичный Circuit-Id OLT: тип circuit-id (0), длина (5), слот (1 байт), PON-порт (1 байт),
+// ONU id (1 байт), VLAN (2 байта). Имя OLT в двоичном формате не передается
+type ponBinaryParser struct{}
+
+func (ponBinaryParser) Name() string {
+	return "pon_binary"
+}
+
+func (ponBinaryParser) ParseCircuitId(circuitIdBytes []byte) (*CircuitId, error) {
+	if len(circuitIdBytes) != 7 {
+		return nil, fmt.Errorf("unexpected circuit-id length %v, expected 7", len(circuitIdBytes))
+	}
+	if circuitIdBytes[0] != 0 || circuitIdBytes[1] != 5 {
+		return nil, fmt.Errorf("unexpected circuit-id type %v with length %v", circuitIdBytes[0], circuitIdBytes[1])
+	}
+	vlan := int(binary.BigEndian.Uint16(circuitIdBytes[5:7]))
+	if vlan > 4095 {
+		return nil, fmt.Errorf("circuit-id contains wrong vlan %v", vlan)
+	}
+	return newPonCircuitId("", "", int(circuitIdBytes[2]), int(circuitIdBytes[3]), int(circuitIdBytes[4]), vlan), nil
+}
+
+func (ponBinaryParser) ParseRemoteId(remoteIdBytes []byte) (string, string) {
+	return ParseRemoteId(remoteIdBytes)
+}
+
+func newPonCircuitId(olt, ponInterface string, slot, ponPort, onuId, vlan int) *CircuitId {
+	return &CircuitId{
+		VlanId:    vlan,
+		Module:    slot,
+		Port:      ponPort,
+		Interface: ponInterface,
+		Olt:       olt,
+		Slot:      &slot,
+		PonPort:   &ponPort,
+		OnuId:     &onuId,
+	}
+}
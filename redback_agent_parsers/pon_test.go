@@ -0,0 +1,86 @@
+package redback_agent_parsers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPonParseCircuitId(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *CircuitId
+		wantErr bool
+	}{
+		{"slot and port", "OLT1 xpon 0/1:12", newPonCircuitId("OLT1", "xpon 0/1", 0, 1, 12, 0), false},
+		{"frame, slot and port", "OLT1 xpon 0/1/3:12", newPonCircuitId("OLT1", "xpon 0/1/3", 1, 3, 12, 0), false},
+		{"vlan after dot", "olt-2 gpon 0/2/7:5.100", newPonCircuitId("olt-2", "gpon 0/2/7", 2, 7, 5, 100), false},
+		{"vlan after colon", "olt-2 EPON 1/4:63:2000", newPonCircuitId("olt-2", "EPON 1/4", 1, 4, 63, 2000), false},
+		{"xgspon", "OLT3 xgspon 0/0/15:127", newPonCircuitId("OLT3", "xgspon 0/0/15", 0, 15, 127, 0), false},
+		{"vlan above 4095", "OLT1 gpon 0/1/3:12.4096", nil, true},
+		{"unknown technology", "OLT1 vdsl 0/1/3:12", nil, true},
+		{"without onu id", "OLT1 gpon 0/1/3", nil, true},
+		{"without olt", "gpon 0/1/3:12", nil, true},
+		{"number overflow", "OLT1 gpon 0/1/99999999999999999999:12", nil, true},
+		{"empty", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ponParser{}.ParseCircuitId([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCircuitId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseCircuitId() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPonBinaryParseCircuitId(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		want    *CircuitId
+		wantErr bool
+	}{
+		{"slot 1 port 3 onu 12 vlan 100", []byte{0x00, 0x05, 0x01, 0x03, 0x0c, 0x00, 0x64}, newPonCircuitId("", "", 1, 3, 12, 100), false},
+		{"max values", []byte{0x00, 0x05, 0xff, 0xff, 0xff, 0x0f, 0xff}, newPonCircuitId("", "", 255, 255, 255, 4095), false},
+		{"vlan above 4095", []byte{0x00, 0x05, 0x01, 0x03, 0x0c, 0x10, 0x00}, nil, true},
+		{"wrong circuit-id type", []byte{0x01, 0x05, 0x01, 0x03, 0x0c, 0x00, 0x64}, nil, true},
+		{"wrong sub-option length", []byte{0x00, 0x04, 0x01, 0x03, 0x0c, 0x00, 0x64}, nil, true},
+		{"d-link circuit-id", []byte{0x00, 0x04, 0x00, 0x64, 0x01, 0x05}, nil, true},
+		{"too long", []byte{0x00, 0x05, 0x01, 0x03, 0x0c, 0x00, 0x64, 0x00}, nil, true},
+		{"empty", []byte{}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ponBinaryParser{}.ParseCircuitId(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCircuitId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseCircuitId() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectPon(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []byte
+		wantParser string
+	}{
+		{"tr-101", []byte("OLT1 xpon 0/1/3:12"), "pon"},
+		{"binary", []byte{0x00, 0x05, 0x01, 0x03, 0x0c, 0x00, 0x64}, "pon_binary"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, _ := Detect(tt.input)
+			if parser == nil || parser.Name() != tt.wantParser {
+				t.Fatalf("Detect() parser = %v, expected %v", parser, tt.wantParser)
+			}
+		})
+	}
+}
@@ -1,22 +1,97 @@
 package redback_agent_parsers
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strings"
 )
 
-func ParseRemoteId(remoteIdBytes []byte) string {
-	if remoteIdBytes == nil || len(remoteIdBytes) <= 2 {
-		return ""
+// Формат Remote-Id в запросе к API (remote_id_type)
+const (
+	RemoteIdAuto  = "auto"
+	RemoteIdMac   = "mac"
+	RemoteIdAscii = "ascii"
+	RemoteIdHex   = "hex"
+)
+
+// Тип подопции Remote-Id в заголовке (тип 1 байт, длина 1 байт)
+const (
+	remoteIdSubTypeMac   = 0
+	remoteIdSubTypeAscii = 1
+)
+
+// ParseRemoteId разбирает Remote-Id по типу и длине подопции: тип 0 длиной 6 - MAC, тип 1 - строка.
+// Remote-Id длиной 6 байт всегда считается MAC без заголовка, даже если его байты похожи на заголовок или печатные.
+// Остальной Remote-Id без заголовка выводится строкой, если все символы печатные, иначе в hex.
+// Возвращает Remote-Id и его формат (mac, ascii или hex)
+func ParseRemoteId(remoteIdBytes []byte) (string, string) {
+	if len(remoteIdBytes) == 0 {
+		return "", ""
+	}
+	data, subType, hasHeader := splitRemoteIdHeader(remoteIdBytes)
+	switch {
+	case len(data) == 6 && (!hasHeader || subType == remoteIdSubTypeMac):
+		return FormatRemoteId(data, RemoteIdMac), RemoteIdMac
+	case hasHeader && subType == remoteIdSubTypeAscii && isPrintable(data):
+		return FormatRemoteId(data, RemoteIdAscii), RemoteIdAscii
+	case hasHeader:
+		return FormatRemoteId(data, RemoteIdHex), RemoteIdHex
+	case isPrintable(data):
+		return FormatRemoteId(data, RemoteIdAscii), RemoteIdAscii
+	default:
+		return FormatRemoteId(data, RemoteIdHex), RemoteIdHex
+	}
+}
+
+// ParseRemoteIdAs выводит Remote-Id в заданном формате (mac, ascii или hex). Заголовок подопции, если он есть, отбрасывается
+func ParseRemoteIdAs(remoteIdBytes []byte, format string) (string, string) {
+	if len(remoteIdBytes) == 0 {
+		return "", ""
+	}
+	data, _, _ := splitRemoteIdHeader(remoteIdBytes)
+	return FormatRemoteId(data, format), format
+}
+
+// FormatRemoteId выводит значение как MAC (00:AD:24:0D:F7:B6), строку или hex
+func FormatRemoteId(data []byte, format string) string {
+	switch format {
+	case RemoteIdMac:
+		parts := make([]string, len(data))
+		for i, b := range data {
+			parts[i] = fmt.Sprintf("%02X", b)
+		}
+		return strings.Join(parts, ":")
+	case RemoteIdAscii:
+		return string(data)
+	default:
+		return hex.EncodeToString(data)
+	}
+}
+
+// ValidRemoteIdFormat проверяет значение remote_id_format
+func ValidRemoteIdFormat(format string) bool {
+	switch format {
+	case "", RemoteIdAuto, RemoteIdMac, RemoteIdAscii, RemoteIdHex:
+		return true
 	}
-	var remoteId string
-	for _, h_block := range remoteIdBytes[2:] {
-		h := fmt.Sprintf("%X", h_block)
-		if len(h) == 1 {
-			h = "0" + h
+	return false
+}
+
+// splitRemoteIdHeader отделяет заголовок подопции (тип 0 или 1, длина), если длина в заголовке совпадает с длиной значения.
+// Значение длиной 6 байт не разделяется: MAC без заголовка может начинаться с 00:04 или 01:04
+func splitRemoteIdHeader(remoteIdBytes []byte) ([]byte, byte, bool) {
+	if len(remoteIdBytes) > 2 && len(remoteIdBytes) != 6 && int(remoteIdBytes[1]) == len(remoteIdBytes)-2 &&
+		(remoteIdBytes[0] == remoteIdSubTypeMac || remoteIdBytes[0] == remoteIdSubTypeAscii) {
+		return remoteIdBytes[2:], remoteIdBytes[0], true
+	}
+	return remoteIdBytes, 0, false
+}
+
+func isPrintable(data []byte) bool {
+	for _, b := range data {
+		if b < 0x20 || b > 0x7e {
+			return false
 		}
-		remoteId += h + ":"
 	}
-	remoteId = strings.Trim(remoteId, ":")
-	return remoteId
+	return true
 }
@@ -0,0 +1,78 @@
+package redback_agent_parsers
+
+import (
+	"testing"
+)
+
+func TestParseRemoteId(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		wantValue string
+		wantType  string
+	}{
+		{"mac with header", []byte{0x00, 0x06, 0x00, 0xad, 0x24, 0x0d, 0xf7, 0xb6}, "00:AD:24:0D:F7:B6", RemoteIdMac},
+		{"mac without header", []byte{0x00, 0xad, 0x24, 0x0d, 0xf7, 0xb6}, "00:AD:24:0D:F7:B6", RemoteIdMac},
+		{"6 bytes looking like type 0 header", []byte{0x00, 0x04, 0x01, 0x02, 0x03, 0x04}, "00:04:01:02:03:04", RemoteIdMac},
+		{"6 bytes looking like type 1 header", []byte{0x01, 0x04, 'a', 'b', 'c', 'd'}, "01:04:61:62:63:64", RemoteIdMac},
+		{"6 printable bytes", []byte("abcdef"), "61:62:63:64:65:66", RemoteIdMac},
+		{"ascii with header", append([]byte{0x01, 0x05}, "hello"...), "hello", RemoteIdAscii},
+		{"ascii without header", []byte("switch-01"), "switch-01", RemoteIdAscii},
+		{"type 1 header with binary value", []byte{0x01, 0x03, 0x00, 0x01, 0x02}, "000102", RemoteIdHex},
+		{"type 0 header with 4 bytes", []byte{0x00, 0x04, 0x0a, 0x00, 0x00, 0x01}, "00:04:0A:00:00:01", RemoteIdMac},
+		{"type 0 header not 6 bytes", []byte{0x00, 0x03, 0x0a, 0x00, 0x01}, "0a0001", RemoteIdHex},
+		{"header length does not match", []byte{0x00, 0x07, 0x0a, 0x00, 0x01}, "00070a0001", RemoteIdHex},
+		{"binary without header", []byte{0xff, 0x00, 0x01}, "ff0001", RemoteIdHex},
+		{"header only", []byte{0x01, 0x00}, "0100", RemoteIdHex},
+		{"single byte", []byte{0x41}, "A", RemoteIdAscii},
+		{"empty", []byte{}, "", ""},
+		{"nil", nil, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, typ := ParseRemoteId(tt.input)
+			if value != tt.wantValue || typ != tt.wantType {
+				t.Fatalf("ParseRemoteId() = %q, %q, expected %q, %q", value, typ, tt.wantValue, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestParseRemoteIdAs(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     []byte
+		format    string
+		wantValue string
+	}{
+		{"mac strips header", []byte{0x00, 0x06, 0x00, 0xad, 0x24, 0x0d, 0xf7, 0xb6}, RemoteIdMac, "00:AD:24:0D:F7:B6"},
+		{"ascii strips header", append([]byte{0x01, 0x03}, "abc"...), RemoteIdAscii, "abc"},
+		{"6 bytes are not split", []byte{0x01, 0x04, 'a', 'b', 'c', 'd'}, RemoteIdHex, "010461626364"},
+		{"hex without header", []byte{0xde, 0xad}, RemoteIdHex, "dead"},
+		{"ascii without header", []byte("abc"), RemoteIdAscii, "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, typ := ParseRemoteIdAs(tt.input, tt.format)
+			if value != tt.wantValue || typ != tt.format {
+				t.Fatalf("ParseRemoteIdAs() = %q, %q, expected %q, %q", value, typ, tt.wantValue, tt.format)
+			}
+		})
+	}
+	if value, typ := ParseRemoteIdAs(nil, RemoteIdMac); value != "" || typ != "" {
+		t.Fatalf("ParseRemoteIdAs(nil) = %q, %q, expected empty", value, typ)
+	}
+}
+
+func TestValidRemoteIdFormat(t *testing.T) {
+	for _, format := range []string{"", RemoteIdAuto, RemoteIdMac, RemoteIdAscii, RemoteIdHex} {
+		if !ValidRemoteIdFormat(format) {
+			t.Fatalf("ValidRemoteIdFormat(%q) = false", format)
+		}
+	}
+	for _, format := range []string{FieldFormatUint, "MAC", "bin"} {
+		if ValidRemoteIdFormat(format) {
+			t.Fatalf("ValidRemoteIdFormat(%q) = true", format)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package redback_agent_parsers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const (
+	FieldFormatUint  = "uint"
+	FieldFormatHex   = "hex"
+	FieldFormatAscii = "ascii"
+	FieldFormatMac   = "mac"
+)
+
+// TemplateConfig - парсер option82, описанный в конфигурации
+type TemplateConfig struct {
+	Name      string   `yaml:"name"`
+	CircuitId Template `yaml:"circuit_id"`
+	RemoteId  Template `yaml:"remote_id"`
+}
+
+// Template - формат Circuit-Id или Remote-Id.
+// length - ожидаемая длина в байтах (0 - любая), regex - регулярное выражение с именованными группами
+// для текстовых значений, fields - поля по смещению, tlv - вложенные подопции (тип 1 байт, длина 1 байт, значение).
+// Заполненные способы применяются вместе, имена полей не должны повторяться
+type Template struct {
+	Length int             `yaml:"length"`
+	Regex  string          `yaml:"regex"`
+	Fields []TemplateField `yaml:"fields"`
+	Tlv    []TemplateTlv   `yaml:"tlv"`
+
+	re *regexp.Regexp
+}
+
+// TemplateField - поле шириной width байт по смещению offset (width: 0 - до конца значения).
+// format: uint (по умолчанию, big-endian), hex, ascii, mac
+type TemplateField struct {
+	Name   string `yaml:"name"`
+	Offset int    `yaml:"offset"`
+	Width  int    `yaml:"width"`
+	Format string `yaml:"format"`
+}
+
+// TemplateTlv - подопция с типом type. Значение целиком записывается в поле name (если задано) в формате format,
+// fields разбирают значение подопции по смещению. Отсутствующие подопции пропускаются
+type TemplateTlv struct {
+	Type   byte            `yaml:"type"`
+	Name   string          `yaml:"name"`
+	Format string          `yaml:"format"`
+	Fields []TemplateField `yaml:"fields"`
+}
+
+// templateParser - парсер по шаблонам из конфигурации.
+// Поля vlan_id, module, port, interface, hostname, olt, slot, pon_port и onu_id Circuit-Id попадают
+// в одноименные поля CircuitId, остальные - в fields.
+// Для Remote-Id используется поле remote_id или первое поле шаблона, формат Remote-Id - формат этого поля (uint - ascii)
+type templateParser struct {
+	conf TemplateConfig
+}
+
+func NewTemplateParser(conf TemplateConfig) (Parser, error) {
+	if conf.Name == "" {
+		return nil, fmt.Errorf("option82 decoder name is empty")
+	}
+	for _, t := range []*Template{&conf.CircuitId, &conf.RemoteId} {
+		if err := t.compile(); err != nil {
+			return nil, fmt.Errorf("option82 decoder %v: %v", conf.Name, err)
+		}
+	}
+	return &templateParser{conf: conf}, nil
+}
+
+func (p *templateParser) Name() string {
+	return p.conf.Name
+}
+
+func (p *templateParser) ParseCircuitId(circuitIdBytes []byte) (*CircuitId, error) {
+	if p.conf.CircuitId.empty() {
+		return nil, fmt.Errorf("circuit-id format is not defined in decoder %v", p.conf.Name)
+	}
+	fields, err := p.conf.CircuitId.decode(circuitIdBytes)
+	if err != nil {
+		return nil, err
+	}
+	circuitId := new(CircuitId)
+	for _, field := range fields {
+		if err := circuitId.setField(field.name, field.value); err != nil {
+			return nil, err
+		}
+	}
+	return circuitId, nil
+}
+
+func (p *templateParser) ParseRemoteId(remoteIdBytes []byte) (string, string) {
+	if p.conf.RemoteId.empty() {
+		return ParseRemoteId(remoteIdBytes)
+	}
+	fields, err := p.conf.RemoteId.decode(remoteIdBytes)
+	if err != nil || len(fields) == 0 {
+		return "", ""
+	}
+	for _, field := range fields {
+		if field.name == "remote_id" {
+			return field.value, remoteIdType(field.format)
+		}
+	}
+	return fields[0].value, remoteIdType(fields[0].format)
+}
+
+// remoteIdType приводит формат поля шаблона к remote_id_type: число (uint) передается строкой
+func remoteIdType(format string) string {
+	if format == FieldFormatUint {
+		return RemoteIdAscii
+	}
+	return format
+}
+
+// setField записывает поле шаблона в CircuitId
+func (c *CircuitId) setField(name, value string) error {
+	var err error
+	switch name {
+	case "vlan_id":
+		c.VlanId, err = strconv.Atoi(value)
+	case "module":
+		c.Module, err = strconv.Atoi(value)
+	case "port":
+		c.Port, err = strconv.Atoi(value)
+	case "interface":
+		c.Interface = value
+	case "hostname":
+		c.Hostname = value
+	case "olt":
+		c.Olt = value
+	case "slot":
+		c.Slot, err = atoiPtr(value)
+	case "pon_port":
+		c.PonPort, err = atoiPtr(value)
+	case "onu_id":
+		c.OnuId, err = atoiPtr(value)
+	default:
+		if c.Fields == nil {
+			c.Fields = make(map[string]string)
+		}
+		c.Fields[name] = value
+	}
+	if err != nil {
+		return fmt.Errorf("field %v must be a number, got '%v'", name, value)
+	}
+	return nil
+}
+
+type templateValue struct {
+	name   string
+	value  string
+	format string
+}
+
+func (t *Template) empty() bool {
+	return t.Length == 0 && t.Regex == "" && len(t.Fields) == 0 && len(t.Tlv) == 0
+}
+
+func (t *Template) compile() error {
+	names := make(map[string]bool)
+	addName := func(name string) error {
+		if name == "" {
+			return fmt.Errorf("field name is empty")
+		}
+		if names[name] {
+			return fmt.Errorf("field %v is defined twice", name)
+		}
+		names[name] = true
+		return nil
+	}
+	if t.Regex != "" {
+		re, err := regexp.Compile(t.Regex)
+		if err != nil {
+			return err
+		}
+		t.re = re
+		for _, name := range re.SubexpNames() {
+			if name == "" {
+				continue
+			}
+			if err := addName(name); err != nil {
+				return err
+			}
+		}
+	}
+	fields := append([]TemplateField{}, t.Fields...)
+	for _, tlv := range t.Tlv {
+		fields = append(fields, tlv.Fields...)
+		if tlv.Name == "" {
+			continue
+		}
+		if err := addName(tlv.Name); err != nil {
+			return err
+		}
+		switch tlv.Format {
+		case "", FieldFormatUint, FieldFormatHex, FieldFormatAscii, FieldFormatMac:
+		default:
+			return fmt.Errorf("unknown format '%v' of field %v", tlv.Format, tlv.Name)
+		}
+	}
+	for _, field := range fields {
+		if err := addName(field.Name); err != nil {
+			return err
+		}
+		if field.Offset < 0 || field.Width < 0 {
+			return fmt.Errorf("field %v has negative offset or width", field.Name)
+		}
+		switch field.Format {
+		case "", FieldFormatUint:
+			if field.Width == 0 || field.Width > 8 {
+				return fmt.Errorf("field %v with format uint must have width from 1 to 8", field.Name)
+			}
+		case FieldFormatHex, FieldFormatAscii, FieldFormatMac:
+		default:
+			return fmt.Errorf("unknown format '%v' of field %v", field.Format, field.Name)
+		}
+	}
+	return nil
+}
+
+func (t *Template) decode(data []byte) ([]templateValue, error) {
+	if t.Length != 0 && len(data) != t.Length {
+		return nil, fmt.Errorf("unexpected length %v, expected %v", len(data), t.Length)
+	}
+	values := make([]templateValue, 0)
+	if t.re != nil {
+		match := t.re.FindStringSubmatch(string(data))
+		if match == nil {
+			return nil, fmt.Errorf("value does not match regex %v", t.Regex)
+		}
+		for i, name := range t.re.SubexpNames() {
+			if name != "" {
+				values = append(values, templateValue{name, match[i], FieldFormatAscii})
+			}
+		}
+	}
+	fieldValues, err := decodeFields(t.Fields, data)
+	if err != nil {
+		return nil, err
+	}
+	values = append(values, fieldValues...)
+	if len(t.Tlv) != 0 {
+		subOptions, err := parseTlv(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, tlv := range t.Tlv {
+			value, ok := subOptions[tlv.Type]
+			if !ok {
+				continue
+			}
+			if tlv.Name != "" {
+				if (tlv.Format == "" || tlv.Format == FieldFormatUint) && len(value) > 8 {
+					return nil, fmt.Errorf("sub-option %v is too long for uint", tlv.Type)
+				}
+				values = append(values, templateValue{tlv.Name, formatField(value, tlv.Format), fieldFormat(tlv.Format)})
+			}
+			fieldValues, err := decodeFields(tlv.Fields, value)
+			if err != nil {
+				return nil, fmt.Errorf("sub-option %v: %v", tlv.Type, err)
+			}
+			values = append(values, fieldValues...)
+		}
+	}
+	return values, nil
+}
+
+func decodeFields(fields []TemplateField, data []byte) ([]templateValue, error) {
+	values := make([]templateValue, 0, len(fields))
+	for _, field := range fields {
+		end := len(data)
+		if field.Width != 0 {
+			end = field.Offset + field.Width
+		}
+		if field.Offset > len(data) || end > len(data) {
+			return nil, fmt.Errorf("field %v is out of value with length %v", field.Name, len(data))
+		}
+		values = append(values, templateValue{field.Name, formatField(data[field.Offset:end], field.Format), fieldFormat(field.Format)})
+	}
+	return values, nil
+}
+
+// parseTlv разбирает подопции: тип (1 байт), длина (1 байт), значение
+func parseTlv(data []byte) (map[byte][]byte, error) {
+	subOptions := make(map[byte][]byte)
+	for pos := 0; pos < len(data); {
+		if pos+2 > len(data) || pos+2+int(data[pos+1]) > len(data) {
+			return nil, fmt.Errorf("sub-option at offset %v is truncated", pos)
+		}
+		subOptions[data[pos]] = data[pos+2 : pos+2+int(data[pos+1])]
+		pos += 2 + int(data[pos+1])
+	}
+	return subOptions, nil
+}
+
+func fieldFormat(format string) string {
+	if format == "" {
+		return FieldFormatUint
+	}
+	return format
+}
+
+func formatField(data []byte, format string) string {
+	switch format {
+	case FieldFormatHex, FieldFormatAscii, FieldFormatMac:
+		return FormatRemoteId(data, format)
+	default:
+		var value uint64
+		for _, b := range data {
+			value = value<<8 | uint64(b)
+		}
+		return strconv.FormatUint(value, 10)
+	}
+}
+
+func atoiPtr(value string) (*int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &number, nil
+}
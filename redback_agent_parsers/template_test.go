@@ -0,0 +1,204 @@
+package redback_agent_parsers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func mustTemplateParser(t *testing.T, conf TemplateConfig) Parser {
+	t.Helper()
+	parser, err := NewTemplateParser(conf)
+	if err != nil {
+		t.Fatalf("NewTemplateParser() error = %v", err)
+	}
+	return parser
+}
+
+func TestTemplateParseCircuitId(t *testing.T) {
+	fields := TemplateConfig{
+		Name: "fields",
+		CircuitId: Template{
+			Length: 8,
+			Fields: []TemplateField{
+				{Name: "vlan_id", Offset: 2, Width: 2},
+				{Name: "slot", Offset: 4, Width: 1},
+				{Name: "port", Offset: 5, Width: 1},
+				{Name: "board", Offset: 6, Width: 2, Format: FieldFormatHex},
+			},
+		},
+	}
+	tail := TemplateConfig{
+		Name: "tail",
+		CircuitId: Template{
+			Fields: []TemplateField{
+				{Name: "module", Offset: 0, Width: 1},
+				{Name: "hostname", Offset: 1, Format: FieldFormatAscii},
+			},
+		},
+	}
+	regex := TemplateConfig{
+		Name: "regex",
+		CircuitId: Template{
+			Regex: `^(?P<hostname>\S+) eth(?P<module>\d+)/(?P<port>\d+):(?P<vlan_id>\d+)$`,
+		},
+	}
+	tlv := TemplateConfig{
+		Name: "tlv",
+		CircuitId: Template{
+			Tlv: []TemplateTlv{
+				{Type: 1, Name: "vlan_id"},
+				{Type: 2, Fields: []TemplateField{
+					{Name: "module", Offset: 0, Width: 1},
+					{Name: "port", Offset: 1, Width: 1},
+				}},
+				{Type: 3, Name: "interface", Format: FieldFormatAscii},
+				{Type: 4, Name: "mac", Format: FieldFormatMac},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		conf    TemplateConfig
+		input   []byte
+		want    *CircuitId
+		wantErr bool
+	}{
+		{"fields", fields, []byte{0x00, 0x06, 0x00, 0x64, 0x02, 0x07, 0xab, 0xcd},
+			&CircuitId{VlanId: 100, Port: 7, Slot: intPtr(2), Fields: map[string]string{"board": "abcd"}}, false},
+		{"fields with wrong length", fields, []byte{0x00, 0x06, 0x00, 0x64, 0x02, 0x07, 0xab}, nil, true},
+		{"field until end", tail, []byte{0x03, 's', 'w', '1'}, &CircuitId{Module: 3, Hostname: "sw1"}, false},
+		{"field until end is empty", tail, []byte{0x03}, &CircuitId{Module: 3}, false},
+		{"field out of value", tail, []byte{}, nil, true},
+		{"regex", regex, []byte("sw1 eth1/12:100"), &CircuitId{Hostname: "sw1", Module: 1, Port: 12, VlanId: 100}, false},
+		{"regex does not match", regex, []byte("sw1 eth1/12"), nil, true},
+		{"tlv", tlv, []byte{0x01, 0x02, 0x00, 0x64, 0x02, 0x02, 0x01, 0x05, 0x03, 0x03, 'g', 'e', '1', 0x04, 0x06, 0x00, 0xad, 0x24, 0x0d, 0xf7, 0xb6},
+			&CircuitId{VlanId: 100, Module: 1, Port: 5, Interface: "ge1", Fields: map[string]string{"mac": "00:AD:24:0D:F7:B6"}}, false},
+		{"tlv with missing sub-options", tlv, []byte{0x02, 0x02, 0x01, 0x05}, &CircuitId{Module: 1, Port: 5}, false},
+		{"tlv truncated value", tlv, []byte{0x01, 0x04, 0x00, 0x64}, nil, true},
+		{"tlv truncated header", tlv, []byte{0x01, 0x02, 0x00, 0x64, 0x02}, nil, true},
+		{"tlv sub-option shorter than fields", tlv, []byte{0x02, 0x01, 0x01}, nil, true},
+		{"tlv uint longer than 8 bytes", tlv, []byte{0x01, 0x09, 1, 2, 3, 4, 5, 6, 7, 8, 9}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mustTemplateParser(t, tt.conf).ParseCircuitId(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCircuitId() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseCircuitId() = %+v, expected %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateParseCircuitIdNotNumber(t *testing.T) {
+	parser := mustTemplateParser(t, TemplateConfig{
+		Name:      "not_number",
+		CircuitId: Template{Fields: []TemplateField{{Name: "port", Format: FieldFormatAscii}}},
+	})
+	if _, err := parser.ParseCircuitId([]byte("ge1")); err == nil {
+		t.Fatal("ParseCircuitId() expected error for non-numeric port")
+	}
+}
+
+func TestTemplateParseRemoteId(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      TemplateConfig
+		input     []byte
+		wantValue string
+		wantType  string
+	}{
+		{"remote_id field", TemplateConfig{Name: "t", RemoteId: Template{Fields: []TemplateField{
+			{Name: "serial", Offset: 0, Width: 2, Format: FieldFormatHex},
+			{Name: "remote_id", Offset: 2, Format: FieldFormatMac},
+		}}}, []byte{0x00, 0x06, 0x00, 0xad, 0x24, 0x0d, 0xf7, 0xb6}, "00:AD:24:0D:F7:B6", RemoteIdMac},
+		{"first field", TemplateConfig{Name: "t", RemoteId: Template{Fields: []TemplateField{
+			{Name: "serial", Offset: 2, Format: FieldFormatAscii},
+		}}}, []byte{0x01, 0x03, 'a', 'b', 'c'}, "abc", RemoteIdAscii},
+		{"uint field is ascii", TemplateConfig{Name: "t", RemoteId: Template{Fields: []TemplateField{
+			{Name: "remote_id", Offset: 0, Width: 2},
+		}}}, []byte{0x01, 0x02}, "258", RemoteIdAscii},
+		{"regex", TemplateConfig{Name: "t", RemoteId: Template{Regex: `^id-(?P<remote_id>\d+)$`}},
+			[]byte("id-42"), "42", RemoteIdAscii},
+		{"truncated value", TemplateConfig{Name: "t", RemoteId: Template{Fields: []TemplateField{
+			{Name: "remote_id", Offset: 2, Width: 6, Format: FieldFormatMac},
+		}}}, []byte{0x00, 0x06, 0x00}, "", ""},
+		{"without template", TemplateConfig{Name: "t"}, []byte{0x00, 0xad, 0x24, 0x0d, 0xf7, 0xb6}, "00:AD:24:0D:F7:B6", RemoteIdMac},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, typ := mustTemplateParser(t, tt.conf).ParseRemoteId(tt.input)
+			if value != tt.wantValue || typ != tt.wantType {
+				t.Fatalf("ParseRemoteId() = %q, %q, expected %q, %q", value, typ, tt.wantValue, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestNewTemplateParserErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		conf TemplateConfig
+	}{
+		{"empty name", TemplateConfig{}},
+		{"bad regex", TemplateConfig{Name: "t", CircuitId: Template{Regex: `(?P<port>\d+`}}},
+		{"empty field name", TemplateConfig{Name: "t", CircuitId: Template{Fields: []TemplateField{{Width: 1}}}}},
+		{"duplicate field", TemplateConfig{Name: "t", CircuitId: Template{
+			Regex:  `^(?P<port>\d+)$`,
+			Fields: []TemplateField{{Name: "port", Width: 1}},
+		}}},
+		{"duplicate tlv field", TemplateConfig{Name: "t", CircuitId: Template{Tlv: []TemplateTlv{
+			{Type: 1, Name: "port"},
+			{Type: 2, Fields: []TemplateField{{Name: "port", Width: 1}}},
+		}}}},
+		{"unknown format", TemplateConfig{Name: "t", CircuitId: Template{Fields: []TemplateField{{Name: "port", Width: 1, Format: "bcd"}}}}},
+		{"unknown tlv format", TemplateConfig{Name: "t", CircuitId: Template{Tlv: []TemplateTlv{{Type: 1, Name: "port", Format: "bcd"}}}}},
+		{"uint without width", TemplateConfig{Name: "t", CircuitId: Template{Fields: []TemplateField{{Name: "port"}}}}},
+		{"uint wider than 8 bytes", TemplateConfig{Name: "t", RemoteId: Template{Fields: []TemplateField{{Name: "remote_id", Width: 9}}}}},
+		{"negative offset", TemplateConfig{Name: "t", CircuitId: Template{Fields: []TemplateField{{Name: "port", Offset: -1, Width: 1}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTemplateParser(tt.conf); err == nil {
+				t.Fatal("NewTemplateParser() expected error")
+			}
+		})
+	}
+}
+
+// Любая обрезка значения не должна приводить к панике ни в одном парсере
+func TestParsersTruncatedInput(t *testing.T) {
+	template := mustTemplateParser(t, TemplateConfig{
+		Name: "truncated",
+		CircuitId: Template{
+			Fields: []TemplateField{{Name: "vlan_id", Offset: 2, Width: 2}},
+			Tlv:    []TemplateTlv{{Type: 0, Fields: []TemplateField{{Name: "port", Offset: 3, Width: 1}}}},
+		},
+		RemoteId: Template{
+			Tlv: []TemplateTlv{{Type: 0, Name: "remote_id", Format: FieldFormatMac}},
+		},
+	})
+	inputs := [][]byte{
+		{0x00, 0x04, 0x00, 0x64, 0x01, 0x05},
+		{0x00, 0x05, 0x01, 0x03, 0x0c, 0x00, 0x64},
+		{0x00, 0x06, 0x00, 0xad, 0x24, 0x0d, 0xf7, 0xb6},
+		[]byte("OLT1 xpon 0/1/3:12.100"),
+		[]byte("sw1 GigabitEthernet0/0/1:100"),
+	}
+	parsers := append(Parsers(), template)
+	for _, input := range inputs {
+		for n := 0; n <= len(input); n++ {
+			for _, parser := range parsers {
+				parser.ParseCircuitId(input[:n])
+				parser.ParseRemoteId(input[:n])
+			}
+			ParseRemoteIdAs(input[:n], RemoteIdMac)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package redback_agent_parsers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Встроенные парсеры. Порядок регистрации - порядок автоопределения:
+// двоичный формат D-Link проверяется первым, текстовые форматы не пересекаются между собой
+func init() {
+	Register(dlinkParser{})
+	Register(newStringParser("huawei", `^(?:(?P<hostname>\S+) )?(?P<interface>(?:Ethernet|GigabitEthernet|XGigabitEthernet|Eth-Trunk)\d+(?:/\d+)*):(?P<vlan>\d+)$`))
+	Register(newStringParser("zte", `^(?P<hostname>\S+) (?P<interface>(?:fei|gei|xgei|smartgroup)_\d+(?:/\d+)*):(?P<vlan>\d+)$`))
+	Register(newStringParser("eltex", `^(?:(?P<hostname>\S+) )?(?P<interface>(?:fa|gi|te|po)\d+(?:/\d+)*):(?P<vlan>\d+)$`))
+	Register(newStringParser("snr", `^Vlan(?P<vlan>\d+)\+(?P<interface>(?:Ethernet|Port-Channel)\d+(?:/\d+)*)$`))
+	Register(newStringParser("bdcom", `^(?:(?P<hostname>\S+) )?(?P<interface>(?:FastEthernet|GigaEthernet|TGigaEthernet|f|g|tg)\d+(?:/\d+)*):(?P<vlan>\d+)$`))
+	Register(ponParser{})
+	Register(ponBinaryParser{})
+}
+
+// dlinkParser - двоичный Circuit-Id D-Link (VLAN, модуль, порт)
+type dlinkParser struct{}
+
+func (dlinkParser) Name() string {
+	return "dlink"
+}
+
+func (dlinkParser) ParseCircuitId(circuitIdBytes []byte) (*CircuitId, error) {
+	return ParseCircuitId(circuitIdBytes)
+}
+
+func (dlinkParser) ParseRemoteId(remoteIdBytes []byte) (string, string) {
+	return ParseRemoteId(remoteIdBytes)
+}
+
+// stringParser - текстовый Circuit-Id вида "<hostname> <интерфейс>:<vlan>".
+// Регулярное выражение содержит группы hostname, interface и vlan.
+// Модуль и порт берутся из номера интерфейса: порт - последнее число, модуль - предпоследнее
+type stringParser struct {
+	name string
+	re   *regexp.Regexp
+}
+
+var interfaceNumbersRe = regexp.MustCompile(`\d+`)
+
+func newStringParser(name, expr string) *stringParser {
+	return &stringParser{name: name, re: regexp.MustCompile(expr)}
+}
+
+func (p *stringParser) Name() string {
+	return p.name
+}
+
+func (p *stringParser) ParseCircuitId(circuitIdBytes []byte) (*CircuitId, error) {
+	match := p.re.FindStringSubmatch(string(circuitIdBytes))
+	if match == nil {
+		return nil, fmt.Errorf("circuit-id does not match %v format", p.name)
+	}
+	circuitId := new(CircuitId)
+	for i, group := range p.re.SubexpNames() {
+		switch group {
+		case "hostname":
+			circuitId.Hostname = match[i]
+		case "interface":
+			circuitId.Interface = match[i]
+		case "vlan":
+			vlan, err := strconv.Atoi(match[i])
+			if err != nil || vlan > 4095 {
+				return nil, fmt.Errorf("circuit-id contains wrong vlan %v", match[i])
+			}
+			circuitId.VlanId = vlan
+		}
+	}
+	numbers := interfaceNumbersRe.FindAllString(circuitId.Interface, -1)
+	if len(numbers) > 0 {
+		circuitId.Port, _ = strconv.Atoi(numbers[len(numbers)-1])
+	}
+	if len(numbers) > 1 {
+		circuitId.Module, _ = strconv.Atoi(numbers[len(numbers)-2])
+	}
+	return circuitId, nil
+}
+
+func (p *stringParser) ParseRemoteId(remoteIdBytes []byte) (string, string) {
+	return ParseRemoteId(remoteIdBytes)
+}
@@ -1,11 +1,19 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/meklis/all-ok-radius-server/admin"
 	"github.com/meklis/all-ok-radius-server/api"
 	"github.com/meklis/all-ok-radius-server/config"
 	"github.com/meklis/all-ok-radius-server/logger"
@@ -19,6 +27,10 @@ var (
 	Config     config.Configuration
 	pathConfig string
 	lg         *logger.Logger
+
+	configLock  sync.Mutex
+	apiInstance *api.Api
+	clients     *radius.Clients
 )
 
 const (
@@ -71,17 +83,153 @@ func main() {
 			}
 		}()
 	}
+	if err := config.Validate(&Config); err != nil {
+		panic(err)
+	}
+
 	//Initialize API
-	apiInstance := api.Init(Config.Api, lg)
+	apiInstance = api.Init(Config.Api, lg)
+
+	//Initialize NAS clients
+	var err error
+	clients, err = radius.NewClients(Config.Radius.Clients, Config.Radius.Secret, lg)
+	if err != nil {
+		panic(err)
+	}
+
+	dictionary, err := radius.LoadDictionary(Config.Radius.Dictionaries)
+	if err != nil {
+		panic(tracerr.Sprint(err))
+	}
+
+	proxy, err := radius.NewProxy(Config.Radius.Proxy, lg)
+	if err != nil {
+		panic(err)
+	}
+
+	option82, err := radius.NewOption82(Config.Radius.Option82)
+	if err != nil {
+		panic(err)
+	}
 
 	//Initialize server
 	rad := radius.Init()
-	err := rad.SetAPI(apiInstance).
+	rad.SetAPI(apiInstance).
 		SetListenAddr(Config.Radius.ListenAddr).
+		SetProto(Config.Radius.Proto).
+		SetListeners(Config.Radius.Listeners).
 		SetLogger(lg).
 		SetSecret(Config.Radius.Secret).
-		ListenAndServe()
+		SetClients(clients).
+		SetFailurePolicy(Config.Radius.OnApiError).
+		SetRadSec(Config.Radius.RadSec).
+		SetDuplicateCacheTTL(Config.Radius.DuplicateCacheTTL).
+		SetDynAuth(Config.Radius.DynAuth).
+		SetDictionary(dictionary).
+		SetForwardAttributes(Config.Radius.ForwardAttributes).
+		SetProxy(proxy).
+		SetOption82(option82).
+		SetRateLimit(Config.Radius.RateLimit).
+		SetWorkers(Config.Radius.Workers)
+
+	//Initialize admin API
+	if Config.Admin.Enabled {
+		go func() {
+			err := admin.Init(Config.Admin, rad, lg).SetReload(reloadConfig).ListenAndServe()
+			lg.CriticalF("Admin API critical err: %v", err)
+			panic(err)
+		}()
+	}
+
+	//Ошибка листенера завершает работу так же, как SIGTERM - с дообработкой запросов и отправкой очередей
+	serveErrors := make(chan error, 1)
+	go func() {
+		serveErrors <- rad.ListenAndServe()
+	}()
+
+	//Перечитывание конфигурации по SIGHUP
+	reloadSignals := make(chan os.Signal, 1)
+	signal.Notify(reloadSignals, syscall.SIGHUP)
+	go func() {
+		for range reloadSignals {
+			lg.NoticeF("received signal SIGHUP, reloading configuration from %v", pathConfig)
+			if err := reloadConfig(); err != nil {
+				lg.ErrorF("configuration is not reloaded: %v", err.Error())
+			}
+		}
+	}()
+
+	//Graceful shutdown - прием запросов останавливается, принятые запросы и очереди событий дорабатываются
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	exitCode := 0
+	reason := ""
+	select {
+	case sig := <-signals:
+		reason = fmt.Sprintf("received signal %v", sig)
+	case err := <-serveErrors:
+		exitCode = 1
+		reason = "radius server stopped"
+		if err != nil {
+			lg.CriticalF("radius server stopped with error: %v", tracerr.Sprint(err))
+			reason = "radius server failed"
+		}
+	}
+	configLock.Lock()
+	shutdownTimeout := Config.ShutdownTimeout
+	configLock.Unlock()
+	if shutdownTimeout == 0 {
+		shutdownTimeout = 10 * time.Second
+	}
+	lg.NoticeF("%v, shutting down (timeout %v)", reason, shutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := rad.Shutdown(ctx); err != nil {
+		lg.ErrorF("radius shutdown: %v", err.Error())
+	}
+	if err := apiInstance.Shutdown(ctx); err != nil {
+		lg.ErrorF("api shutdown: %v", tracerr.Sprint(err))
+	}
+	lg.NoticeF("radius-server stopped")
+	if exitCode != 0 {
+		cancel()
+		os.Exit(exitCode)
+	}
+}
+
+// reloadConfig перечитывает файл конфигурации и применяет адреса и таймауты API, настройки кеша,
+// клиентов и секреты, уровень логирования. Кеш ответов API сохраняется.
+// Если конфигурация содержит ошибку, текущая конфигурация не меняется
+func reloadConfig() error {
+	configLock.Lock()
+	defer configLock.Unlock()
+	var newConf config.Configuration
+	if err := config.ReloadConfig(pathConfig, &newConf); err != nil {
+		return err
+	}
+	if err := config.Validate(&newConf); err != nil {
+		return err
+	}
+	//Все, что может завершиться ошибкой, строится до применения - конфигурация применяется целиком или не применяется
+	newClients, err := radius.NewClients(newConf.Radius.Clients, newConf.Radius.Secret, lg)
 	if err != nil {
-		panic(tracerr.Sprint(err))
+		return err
+	}
+	if err := apiInstance.Reload(newConf.Api); err != nil {
+		return err
+	}
+	clients.Reload(newClients)
+	if newConf.Logger.Console.Enabled {
+		lg.SetLogLevel(logger.LogLevel(newConf.Logger.Console.LogLevel))
+	}
+	if sections := config.NotReloadable(&Config, &newConf); len(sections) != 0 {
+		lg.WarningF("changes in sections %v will be applied after restart", strings.Join(sections, ", "))
+	}
+	applied := "none"
+	if sections := config.Reloaded(&Config, &newConf); len(sections) != 0 {
+		applied = strings.Join(sections, ", ")
 	}
+	Config = newConf
+	lg.NoticeF("configuration reloaded from %v, applied changes: %v", pathConfig, applied)
+	return nil
 }